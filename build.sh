@@ -2,4 +2,6 @@
 
 set -eu
 
-go build -o bin/renovator ./cmd/renovator
+VERSION=$(git describe --tags --always --dirty 2>/dev/null || echo dev)
+
+go build -ldflags "-X main.version=${VERSION}" -o bin/renovator ./cmd/renovator
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// actionInputs maps the inputs of the GitHub Action, as passed in INPUT_<NAME> environment variables, to flags.
+var actionInputs = []struct{ input, flag string }{
+	{"TOKEN", "token"},
+	{"PROVIDER", "provider"},
+	{"BASE_URL", "base-url"},
+	{"CONFIG", "config"},
+	{"ORG", "o"},
+	{"USER", "u"},
+	{"REPO", "r"},
+	{"AUTHOR", "a"},
+	{"DEPENDENCY", "d"},
+	{"COMMENT", "m"},
+	{"RETRY_UNTIL_ALL_MERGED", "retry-until-all-merged"},
+	{"DEBUG", "debug"},
+}
+
+// actionArgs returns the command line for "renovator action", built from the INPUT_* environment variables set
+// by the GitHub Actions runner. Action runs are never interactive, so matching PRs are always approved.
+func actionArgs() []string {
+	args := []string{"-y"}
+	for _, input := range actionInputs {
+		if value := strings.TrimSpace(os.Getenv("INPUT_" + input.input)); value != "" {
+			args = append(args, fmt.Sprintf("-%s=%s", input.flag, value))
+		}
+	}
+	return args
+}
+
+// writeActionOutputs writes the merged and failed counts and the skipped PRs of the report as step outputs to the
+// GITHUB_OUTPUT file.
+func (r *report) writeActionOutputs(path string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	type skippedPR struct {
+		URL    string `json:"url"`
+		Title  string `json:"title"`
+		Reason string `json:"reason"`
+	}
+	var merged, failed int
+	skipped := []skippedPR{}
+	for _, result := range r.results {
+		switch result.Decision {
+		case decisionMerged:
+			merged++
+		case decisionFailed:
+			failed++
+		case decisionSkipped:
+			skipped = append(skipped, skippedPR{URL: result.PR.URL, Title: result.PR.Title, Reason: result.Reason})
+		}
+	}
+	skippedJSON, err := json.Marshal(skipped)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(f, "merged=%d\nfailed=%d\nskipped=%s\n", merged, failed, skippedJSON)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+const (
+	defaultAlertConsecutiveFailures = 3
+	defaultAlertErrorRate           = 0.5
+	pagerDutyEventsURL              = "https://events.pagerduty.com/v2/enqueue"
+	defaultOpsgenieURL              = "https://api.opsgenie.com"
+)
+
+// alertingConfig configures paging when renovation is stuck: a PR failing to merge ConsecutiveFailures passes in a
+// row, or more than ErrorRate of the PRs of a pass failing.
+type alertingConfig struct {
+	PagerDuty           *pagerDutyConfig `yaml:"pagerduty"`
+	Opsgenie            *opsgenieConfig  `yaml:"opsgenie"`
+	ConsecutiveFailures int              `yaml:"consecutiveFailures"`
+	ErrorRate           float64          `yaml:"errorRate"`
+}
+
+type pagerDutyConfig struct {
+	// RoutingKeyVariable is the environment variable holding the Events API v2 integration key.
+	RoutingKeyVariable string `yaml:"routingKeyVariable"`
+}
+
+type opsgenieConfig struct {
+	// URL is the API URL, https://api.opsgenie.com by default or https://api.eu.opsgenie.com in the EU.
+	URL            string `yaml:"url"`
+	APIKeyVariable string `yaml:"apiKeyVariable"`
+}
+
+// alerter raises and resolves alerts identified by a key, so that an alert raised on every pass pages only once.
+type alerter interface {
+	trigger(ctx context.Context, key, summary string) error
+	resolve(ctx context.Context, key string) error
+}
+
+// alertMonitor tracks failures across the passes of a daemon and raises alerts when they persist.
+type alertMonitor struct {
+	alerters            []alerter
+	consecutiveFailures int
+	errorRate           float64
+	// source identifies the targets in alerts, so that daemons over different targets raise separate alerts.
+	source string
+
+	failures map[string]int
+	open     map[string]bool
+}
+
+// newAlertMonitor creates a monitor for the alerters configured, returning nil when there are none.
+func newAlertMonitor(c *alertingConfig, targets []targetConfig) (*alertMonitor, error) {
+	if c == nil {
+		return nil, nil
+	}
+	var alerters []alerter
+	if c.PagerDuty != nil {
+		key := os.Getenv(c.PagerDuty.RoutingKeyVariable)
+		if key == "" {
+			return nil, fmt.Errorf("PagerDuty routing key variable %q is empty", c.PagerDuty.RoutingKeyVariable)
+		}
+		alerters = append(alerters, &pagerDutyAlerter{routingKey: key})
+	}
+	if c.Opsgenie != nil {
+		key := os.Getenv(c.Opsgenie.APIKeyVariable)
+		if key == "" {
+			return nil, fmt.Errorf("Opsgenie API key variable %q is empty", c.Opsgenie.APIKeyVariable)
+		}
+		base := c.Opsgenie.URL
+		if base == "" {
+			base = defaultOpsgenieURL
+		}
+		api := newRESTClient(strings.TrimSuffix(base, "/")+"/v2", http.DefaultClient)
+		api.header.Set("Authorization", "GenieKey "+key)
+		alerters = append(alerters, &opsgenieAlerter{api: api})
+	}
+	if len(alerters) == 0 {
+		return nil, nil
+	}
+
+	m := &alertMonitor{
+		alerters:            alerters,
+		consecutiveFailures: c.ConsecutiveFailures,
+		errorRate:           c.ErrorRate,
+		failures:            map[string]int{},
+		open:                map[string]bool{},
+	}
+	if m.consecutiveFailures <= 0 {
+		m.consecutiveFailures = defaultAlertConsecutiveFailures
+	}
+	if m.errorRate <= 0 {
+		m.errorRate = defaultAlertErrorRate
+	}
+	names := make([]string, 0, len(targets))
+	for _, target := range targets {
+		names = append(names, target.String())
+	}
+	m.source = strings.Join(names, ", ")
+	return m, nil
+}
+
+// observe updates the failure counts with the results of a pass, raising alerts for PRs that have failed too many
+// passes in a row and for a too high error rate, and resolving alerts that no longer apply. A nil monitor does
+// nothing.
+func (m *alertMonitor) observe(ctx context.Context, summary runSummary) {
+	if m == nil {
+		return
+	}
+	failed := map[string]bool{}
+	for _, result := range summary.Failed {
+		key := "renovator " + result.PR.URL
+		failed[key] = true
+		m.failures[key]++
+		if m.failures[key] >= m.consecutiveFailures {
+			m.trigger(ctx, key, fmt.Sprintf("Renovate PR %s/%s#%d has failed to merge %d passes in a row: %s",
+				result.PR.Org, result.PR.Repo, result.PR.Number, m.failures[key], result.Reason))
+		}
+	}
+	// PRs that did not fail this pass were merged, skipped or are gone
+	for key := range m.failures {
+		if !failed[key] {
+			delete(m.failures, key)
+			m.resolve(ctx, key)
+		}
+	}
+
+	// broken merges are alerted on once, as later passes do not see their PRs again
+	for _, result := range summary.Broken {
+		m.trigger(ctx, "renovator broken merge "+result.PR.URL, brokenSummary(result))
+	}
+
+	key := "renovator error rate " + m.source
+	total := len(summary.Merged) + len(summary.Skipped) + len(summary.Failed)
+	if total > 0 && float64(len(summary.Failed))/float64(total) > m.errorRate {
+		m.trigger(ctx, key, fmt.Sprintf("Renovator failed to merge %d of %d PRs of %s",
+			len(summary.Failed), total, m.source))
+	} else {
+		m.resolve(ctx, key)
+	}
+}
+
+func (m *alertMonitor) trigger(ctx context.Context, key, summary string) {
+	if m.open[key] {
+		return
+	}
+	log.Printf("Alerting: %s", summary)
+	for _, a := range m.alerters {
+		if err := a.trigger(ctx, key, summary); err != nil {
+			log.Printf("Error raising alert: %v", err)
+		}
+	}
+	m.open[key] = true
+}
+
+func (m *alertMonitor) resolve(ctx context.Context, key string) {
+	if !m.open[key] {
+		return
+	}
+	for _, a := range m.alerters {
+		if err := a.resolve(ctx, key); err != nil {
+			log.Printf("Error resolving alert: %v", err)
+		}
+	}
+	delete(m.open, key)
+}
+
+// pagerDutyAlerter raises alerts with the PagerDuty Events API v2.
+type pagerDutyAlerter struct {
+	routingKey string
+}
+
+func (p *pagerDutyAlerter) trigger(ctx context.Context, key, summary string) error {
+	return postJSON(ctx, pagerDutyEventsURL, map[string]any{
+		"routing_key":  p.routingKey,
+		"event_action": "trigger",
+		"dedup_key":    truncate(key, 255),
+		"payload": map[string]string{
+			"summary":  truncate(summary, 1024),
+			"source":   "renovator",
+			"severity": "error",
+		},
+	})
+}
+
+func (p *pagerDutyAlerter) resolve(ctx context.Context, key string) error {
+	return postJSON(ctx, pagerDutyEventsURL, map[string]string{
+		"routing_key":  p.routingKey,
+		"event_action": "resolve",
+		"dedup_key":    truncate(key, 255),
+	})
+}
+
+// opsgenieAlerter raises alerts with the Opsgenie Alert API, using the key as the alias.
+type opsgenieAlerter struct {
+	api *restClient
+}
+
+func (o *opsgenieAlerter) trigger(ctx context.Context, key, summary string) error {
+	return o.api.do(ctx, http.MethodPost, "/alerts", map[string]string{
+		"message":     truncate(summary, 130),
+		"description": summary,
+		"alias":       truncate(key, 512),
+		"source":      "renovator",
+	}, nil)
+}
+
+func (o *opsgenieAlerter) resolve(ctx context.Context, key string) error {
+	path := "/alerts/" + url.PathEscape(truncate(key, 512)) + "/close?identifierType=alias"
+	return o.api.do(ctx, http.MethodPost, path, map[string]string{"source": "renovator"}, nil)
+}
+
+// truncate shortens s to at most limit runes, ending it with an ellipsis when shortened.
+func truncate(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return string(runes[:limit-1]) + "…"
+}
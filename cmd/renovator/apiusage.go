@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// countingTransport counts API calls made through it, split by GitHub rate limit category.
+type countingTransport struct {
+	base   http.RoundTripper
+	core   atomic.Int64
+	search atomic.Int64
+}
+
+func newCountingTransport(base http.RoundTripper) *countingTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &countingTransport{base: base}
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	switch {
+	case strings.HasSuffix(req.URL.Path, "/rate_limit"):
+		// querying rate limits does not count against them
+	case strings.HasPrefix(req.URL.Path, "/search/") || strings.Contains(req.URL.Path, "/api/v3/search/"):
+		t.search.Add(1)
+	default:
+		t.core.Add(1)
+	}
+	return t.base.RoundTrip(req)
+}
+
+// apiUsage is a snapshot of calls made so far.
+type apiUsage struct {
+	core, search int64
+}
+
+func (t *countingTransport) snapshot() apiUsage {
+	return apiUsage{core: t.core.Load(), search: t.search.Load()}
+}
+
+func (u apiUsage) since(earlier apiUsage) apiUsage {
+	return apiUsage{core: u.core - earlier.core, search: u.search - earlier.search}
+}
+
+// rateLimit is the state of one API rate limit category.
+type rateLimit struct {
+	Name      string
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+// rateLimitProvider is implemented by providers that can report their remaining API rate limits.
+type rateLimitProvider interface {
+	RateLimits(ctx context.Context) ([]rateLimit, error)
+}
+
+// calls returns the number of calls counted against the named rate limit category.
+func (u apiUsage) calls(category string) int64 {
+	if category == "search" {
+		return u.search
+	}
+	return u.core
+}
+
+// reportAPIUsage prints the number of calls made in a pass alongside the remaining rate limits and how many
+// more passes of the same size those limits would allow.
+func reportAPIUsage(ctx context.Context, provider Provider, label string, used apiUsage) {
+	infof("\n"+msg("API usage (%s): %d core calls, %d search calls")+"\n", label, used.core, used.search)
+
+	limiter, ok := provider.(rateLimitProvider)
+	if !ok || verbosity < 1 {
+		return
+	}
+	limits, err := limiter.RateLimits(ctx)
+	if err != nil {
+		log.Printf("Error fetching rate limits: %v", err)
+		return
+	}
+	for _, limit := range limits {
+		printRateLimit(limit, used.calls(limit.Name))
+	}
+}
+
+func printRateLimit(limit rateLimit, used int64) {
+	reset := time.Until(limit.Reset).Round(time.Second)
+	fmt.Printf("  "+msg("%s: %d/%d remaining, resets in %s"), limit.Name, limit.Remaining, limit.Limit, reset)
+	if used > 0 {
+		fmt.Printf(msg(", capacity for ~%d more passes like this one"), int64(limit.Remaining)/used)
+	}
+	fmt.Println()
+}
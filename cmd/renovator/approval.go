@@ -0,0 +1,48 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/template"
+)
+
+// parseApprovalComment parses the -m approval comment as a template executed with templateData for each PR. A
+// comment of @path is read from the file at path, and @- from stdin.
+func parseApprovalComment(comment string) (*template.Template, error) {
+	if path, ok := strings.CutPrefix(comment, "@"); ok {
+		var data []byte
+		var err error
+		if path == "-" {
+			data, err = io.ReadAll(os.Stdin)
+		} else {
+			data, err = os.ReadFile(path)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading approval comment: %w", err)
+		}
+		comment = strings.TrimRight(string(data), "\r\n")
+	}
+	tmpl, err := template.New("approval").Parse(comment)
+	if err != nil {
+		return nil, err
+	}
+	if err := tmpl.Execute(io.Discard, templateData{}); err != nil {
+		return nil, err
+	}
+	return tmpl, nil
+}
+
+// approvalComment renders the comment pr is approved with.
+func (r *runner) approvalComment(pr *PullRequest) (string, error) {
+	if r.opts.approvalComment == nil {
+		return r.opts.defaultComment, nil
+	}
+	var b bytes.Buffer
+	if err := r.opts.approvalComment.Execute(&b, newTemplateData(pr)); err != nil {
+		return "", fmt.Errorf("rendering approval comment: %w", err)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// advisoryID matches the GitHub security advisory and CVE identifiers Renovate links vulnerability fixes to.
+var advisoryID = regexp.MustCompile(`\bGHSA(?:-[23456789cfghjmpqrvwx]{4}){3}\b|\bCVE-\d{4}-\d{4,}\b`)
+
+// auditEntry is an open bot PR as reported by renovator audit.
+type auditEntry struct {
+	Target    string    `json:"target"`
+	Org       string    `json:"org"`
+	Repo      string    `json:"repo"`
+	Number    int       `json:"number"`
+	Title     string    `json:"title"`
+	URL       string    `json:"url"`
+	Author    string    `json:"author"`
+	Update    update    `json:"update"`
+	CreatedAt time.Time `json:"createdAt"`
+	// Checks is passing, failing or pending.
+	Checks string `json:"checks"`
+	// Security is set for updates fixing a vulnerability, and Advisories are the advisories they link to.
+	Security   bool     `json:"security"`
+	Advisories []string `json:"advisories,omitempty"`
+	Error      string   `json:"error,omitempty"`
+}
+
+// audit returns all open PRs of the bot in the org, or the repository, regardless of review requests. It only
+// reads, so that it can be run with a read-only token.
+func (r *runner) audit(ctx context.Context) ([]auditEntry, error) {
+	query := SearchQuery{Org: r.opts.org, Repo: r.opts.repo, Author: r.opts.author}
+	prs, err := r.searchPRs(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("error searching PRs: %w", err)
+	}
+	if prs, err = r.filterPaths(ctx, prs); err != nil {
+		return nil, err
+	}
+	if prs, err = r.filterRepos(ctx, prs); err != nil {
+		return nil, err
+	}
+	sortPRs(prs, r.opts.sortBy)
+
+	var entries []auditEntry
+	for _, pr := range prs {
+		if r.opts.dependency != "" && !r.matchesDependency(ctx, pr) {
+			continue
+		}
+		entries = append(entries, r.auditEntry(ctx, pr))
+	}
+	return entries, nil
+}
+
+func (r *runner) auditEntry(ctx context.Context, pr *PullRequest) auditEntry {
+	e := auditEntry{
+		Target: r.target, Org: pr.Org, Repo: pr.Repo, Number: pr.Number, Title: pr.Title, URL: pr.URL, Author: pr.Author,
+		Update: parseUpdate(pr), CreatedAt: pr.CreatedAt, Advisories: advisories(pr.Body),
+	}
+	e.Security = strings.Contains(strings.ToUpper(pr.Title), "[SECURITY]") || len(e.Advisories) > 0
+	prDetails, err := r.provider.GetPR(ctx, pr.Org, pr.Repo, pr.Number)
+	if err != nil {
+		e.Error = err.Error()
+		return e
+	}
+	passed, err := r.provider.EvaluateChecks(ctx, prDetails)
+	switch {
+	case err != nil:
+		e.Error = err.Error()
+	case passed:
+		e.Checks = "passing"
+	case r.checksCode(ctx, prDetails) == reasonChecksPending:
+		e.Checks = "pending"
+	default:
+		e.Checks = "failing"
+	}
+	return e
+}
+
+// advisories returns the advisories linked from the description of a PR in the order they are first mentioned.
+func advisories(body string) []string {
+	var ids []string
+	for _, id := range advisoryID.FindAllString(body, -1) {
+		if !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// writeAudit writes the entries as a table followed by totals for compliance reviews, or as JSON. The totals state
+// the incomplete searches the entries are missing PRs of.
+func writeAudit(w io.Writer, entries []auditEntry, incomplete []string, output string) error {
+	if output == "json" {
+		if entries == nil {
+			entries = []auditEntry{}
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(entries)
+	}
+
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, msg("PR\tUPDATE\tAGE\tCHECKS\tSECURITY"))
+	var failing, security int
+	var oldest time.Duration
+	for _, e := range entries {
+		age := time.Since(e.CreatedAt)
+		oldest = max(oldest, age)
+		change := e.Title
+		if e.Update.Dependency != "" && e.Update.From != "" {
+			change = fmt.Sprintf("%s %s -> %s", e.Update.Dependency, e.Update.From, e.Update.To)
+		}
+		checks := e.Checks
+		if e.Error != "" {
+			checks = "error: " + e.Error
+		} else if checks == "failing" {
+			failing++
+		}
+		vulnerability := ""
+		if e.Security {
+			security++
+			vulnerability = "yes"
+			if len(e.Advisories) > 0 {
+				vulnerability = strings.Join(e.Advisories, ", ")
+			}
+		}
+		fmt.Fprintf(table, "%s/%s#%d\t%s\t%s\t%s\t%s\n", e.Org, e.Repo, e.Number, change, formatAge(age), checks,
+			vulnerability)
+	}
+	if err := table.Flush(); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "\n"+msg("%d open PRs, %d with failing checks, %d fixing vulnerabilities, oldest %s")+"\n",
+		len(entries), failing, security, formatAge(oldest)); err != nil {
+		return err
+	}
+	for _, search := range incomplete {
+		if _, err := fmt.Fprintf(w, msg("Incomplete, %s")+"\n", search); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+)
+
+// awsSecretToken reads a token from AWS Secrets Manager using the default AWS credential chain. The secret can
+// either hold the token as plain text or a JSON object with a "token" key.
+func awsSecretToken(ctx context.Context, secretID string) (string, error) {
+	cfg, err := awsconfig.LoadDefaultConfig(ctx)
+	if err != nil {
+		return "", fmt.Errorf("loading AWS config: %w", err)
+	}
+	out, err := secretsmanager.NewFromConfig(cfg).GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(secretID),
+	})
+	if err != nil {
+		return "", fmt.Errorf("reading AWS secret %s: %w", secretID, err)
+	}
+
+	value := strings.TrimSpace(aws.ToString(out.SecretString))
+	if strings.HasPrefix(value, "{") {
+		var fields map[string]string
+		if err := json.Unmarshal([]byte(value), &fields); err != nil {
+			return "", fmt.Errorf("parsing AWS secret %s: %w", secretID, err)
+		}
+		value = fields["token"]
+	}
+	if value == "" {
+		return "", fmt.Errorf("AWS secret %s holds no token", secretID)
+	}
+	return value, nil
+}
+
+// awsParameterToken reads a token from an AWS Systems Manager parameter, decrypting SecureString parameters.
+func awsParameterToken(ctx context.Context, name string) (string, error) {
+	cfg, err := awsconfig.LoadDefaultConfig(ctx)
+	if err != nil {
+		return "", fmt.Errorf("loading AWS config: %w", err)
+	}
+	out, err := ssm.NewFromConfig(cfg).GetParameter(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(name),
+		WithDecryption: aws.Bool(true),
+	})
+	if err != nil {
+		return "", fmt.Errorf("reading AWS parameter %s: %w", name, err)
+	}
+	var value string
+	if out.Parameter != nil {
+		value = strings.TrimSpace(aws.ToString(out.Parameter.Value))
+	}
+	if value == "" {
+		return "", fmt.Errorf("AWS parameter %s is empty", name)
+	}
+	return value, nil
+}
@@ -0,0 +1,357 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const azureDevOpsAPIVersion = "7.1"
+
+// Policy type IDs of Azure DevOps build validation and status check policies. Other blocking policies, such
+// as minimum reviewer counts, are satisfied by renovator itself and so are not part of check evaluation.
+var azureDevOpsCheckPolicyTypes = map[string]bool{
+	"0609b952-1397-4640-95ec-e00a01b2c241": true, // Build
+	"cbdc66da-9728-4af8-aada-9a5a32e4a226": true, // Status
+}
+
+// azureDevOpsProvider implements Provider for Azure DevOps Repos. The base URL is the organization URL
+// (https://dev.azure.com/<organization>) and the org of a query is the Azure DevOps project.
+//
+// Authors and reviewers are matched against either the unique name (usually the e-mail address) or the
+// display name of the identity.
+type azureDevOpsProvider struct {
+	api     *restClient
+	baseURL string
+
+	mu     sync.Mutex
+	selfID string
+	// identityIDs are the identity IDs of PR authors by name, "" for names that could not be resolved.
+	identityIDs map[string]string
+}
+
+func newAzureDevOpsProvider(baseURL string, httpClient *http.Client) *azureDevOpsProvider {
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	return &azureDevOpsProvider{api: newRESTClient(baseURL, httpClient), baseURL: baseURL}
+}
+
+type azureDevOpsIdentity struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"displayName"`
+	UniqueName  string `json:"uniqueName"`
+}
+
+func (i azureDevOpsIdentity) is(name string) bool {
+	return strings.EqualFold(i.UniqueName, name) || strings.EqualFold(i.DisplayName, name)
+}
+
+type azureDevOpsPullRequest struct {
+	PullRequestID int       `json:"pullRequestId"`
+	Title         string    `json:"title"`
+	Description   string    `json:"description"`
+	CreationDate  time.Time `json:"creationDate"`
+	Status        string    `json:"status"`
+	MergeStatus   string    `json:"mergeStatus"`
+	IsDraft       bool      `json:"isDraft"`
+	SourceRefName string    `json:"sourceRefName"`
+	Repository    struct {
+		Name    string `json:"name"`
+		Project struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"project"`
+	} `json:"repository"`
+	CreatedBy             azureDevOpsIdentity   `json:"createdBy"`
+	AutoCompleteSetBy     *azureDevOpsIdentity  `json:"autoCompleteSetBy"`
+	Reviewers             []azureDevOpsIdentity `json:"reviewers"`
+	LastMergeSourceCommit struct {
+		CommitID string `json:"commitId"`
+	} `json:"lastMergeSourceCommit"`
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+}
+
+func (p *azureDevOpsProvider) webURL(pr *azureDevOpsPullRequest) string {
+	return fmt.Sprintf("%s/%s/_git/%s/pullrequest/%d", p.baseURL,
+		url.PathEscape(pr.Repository.Project.Name), url.PathEscape(pr.Repository.Name), pr.PullRequestID)
+}
+
+func (p *azureDevOpsProvider) toPullRequest(pr *azureDevOpsPullRequest) *PullRequest {
+	labels := make([]string, 0, len(pr.Labels))
+	for _, label := range pr.Labels {
+		labels = append(labels, label.Name)
+	}
+	return &PullRequest{
+		Org:       pr.Repository.Project.Name,
+		Repo:      pr.Repository.Name,
+		Number:    pr.PullRequestID,
+		Title:     pr.Title,
+		Body:      pr.Description,
+		URL:       p.webURL(pr),
+		HeadSHA:   pr.LastMergeSourceCommit.CommitID,
+		Merged:    pr.Status == "completed",
+		Closed:    pr.Status == "abandoned",
+		Mergeable: pr.MergeStatus == "succeeded",
+		CreatedAt: pr.CreationDate,
+		AutoMerge: pr.AutoCompleteSetBy != nil,
+		Draft:     pr.IsDraft,
+		HeadRef:   strings.TrimPrefix(pr.SourceRefName, "refs/heads/"),
+		Labels:    labels,
+	}
+}
+
+func (p *azureDevOpsProvider) repoPath(project, repo string) string {
+	return fmt.Sprintf("/%s/_apis/git/repositories/%s", url.PathEscape(project), url.PathEscape(repo))
+}
+
+func (p *azureDevOpsProvider) SearchUpdatePRs(ctx context.Context, query SearchQuery) ([]*PullRequest, error) {
+	top := query.PageSize
+	if top == 0 {
+		top = 100
+	}
+	params := url.Values{}
+	params.Set("searchCriteria.status", "active")
+	if creatorID := p.identityID(ctx, query.Author); creatorID != "" {
+		params.Set("searchCriteria.creatorId", creatorID)
+	}
+	params.Set("$top", fmt.Sprint(top))
+	params.Set("api-version", azureDevOpsAPIVersion)
+
+	path := fmt.Sprintf("/%s/_apis/git/pullrequests", url.PathEscape(query.Org))
+	if query.Repo != "" {
+		path = p.repoPath(query.Org, query.Repo) + "/pullrequests"
+	}
+
+	var prs []*PullRequest
+	for skip := 0; ; skip += top {
+		params.Set("$skip", fmt.Sprint(skip))
+		var result struct {
+			Value []azureDevOpsPullRequest `json:"value"`
+		}
+		if err := p.api.do(ctx, http.MethodGet, path+"?"+params.Encode(), nil, &result); err != nil {
+			return nil, err
+		}
+		for i := range result.Value {
+			pr := &result.Value[i]
+			if !pr.CreatedBy.is(query.Author) {
+				continue
+			}
+			if query.Repo == "" && query.User != "" && !azureDevOpsHasReviewer(pr, query.User) {
+				continue
+			}
+			prs = append(prs, p.toPullRequest(pr))
+		}
+		if len(result.Value) < top {
+			return prs, nil
+		}
+	}
+}
+
+// identityID returns the identity ID of the PR author name, so that searches only return the PRs of the author,
+// or "" when it cannot be resolved and PRs are filtered by their creator after fetching them.
+func (p *azureDevOpsProvider) identityID(ctx context.Context, name string) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if id, ok := p.identityIDs[name]; ok {
+		return id
+	}
+	params := url.Values{}
+	params.Set("searchFilter", "General")
+	params.Set("filterValue", name)
+	params.Set("api-version", azureDevOpsAPIVersion)
+	var identities struct {
+		Value []struct {
+			ID string `json:"id"`
+		} `json:"value"`
+	}
+	id := ""
+	if err := p.api.do(ctx, http.MethodGet, "/_apis/identities?"+params.Encode(), nil, &identities); err != nil {
+		verbosef("Error resolving the identity of %s, filtering PRs by creator name: %v\n", name, err)
+	} else if len(identities.Value) == 1 {
+		id = identities.Value[0].ID
+	}
+	if p.identityIDs == nil {
+		p.identityIDs = map[string]string{}
+	}
+	p.identityIDs[name] = id
+	return id
+}
+
+func azureDevOpsHasReviewer(pr *azureDevOpsPullRequest, user string) bool {
+	for _, reviewer := range pr.Reviewers {
+		if reviewer.is(user) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *azureDevOpsProvider) getPullRequest(ctx context.Context, project, repo string, number int) (*azureDevOpsPullRequest, error) {
+	var pr azureDevOpsPullRequest
+	path := fmt.Sprintf("%s/pullrequests/%d?api-version=%s", p.repoPath(project, repo), number, azureDevOpsAPIVersion)
+	if err := p.api.do(ctx, http.MethodGet, path, nil, &pr); err != nil {
+		return nil, err
+	}
+	return &pr, nil
+}
+
+func (p *azureDevOpsProvider) GetPR(ctx context.Context, org, repo string, number int) (*PullRequest, error) {
+	pr, err := p.getPullRequest(ctx, org, repo, number)
+	if err != nil {
+		return nil, err
+	}
+	return p.toPullRequest(pr), nil
+}
+
+// EvaluateChecks evaluates the build validation and status policies applying to the PR.
+func (p *azureDevOpsProvider) EvaluateChecks(ctx context.Context, pr *PullRequest) (bool, error) {
+	details, err := p.getPullRequest(ctx, pr.Org, pr.Repo, pr.Number)
+	if err != nil {
+		return false, err
+	}
+
+	params := url.Values{}
+	params.Set("artifactId", fmt.Sprintf("vstfs:///CodeReview/CodeReviewId/%s/%d", details.Repository.Project.ID, pr.Number))
+	params.Set("api-version", azureDevOpsAPIVersion+"-preview.1")
+	var evaluations struct {
+		Value []struct {
+			Status        string `json:"status"`
+			Configuration struct {
+				IsBlocking bool `json:"isBlocking"`
+				Type       struct {
+					ID string `json:"id"`
+				} `json:"type"`
+			} `json:"configuration"`
+		} `json:"value"`
+	}
+	path := fmt.Sprintf("/%s/_apis/policy/evaluations?%s", url.PathEscape(pr.Org), params.Encode())
+	if err := p.api.do(ctx, http.MethodGet, path, nil, &evaluations); err != nil {
+		return false, err
+	}
+	for _, evaluation := range evaluations.Value {
+		if !evaluation.Configuration.IsBlocking || !azureDevOpsCheckPolicyTypes[evaluation.Configuration.Type.ID] {
+			continue
+		}
+		if evaluation.Status != "approved" && evaluation.Status != "notApplicable" {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// authenticatedUserID returns the identity ID of the token owner, needed to cast a vote.
+func (p *azureDevOpsProvider) authenticatedUserID(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.selfID != "" {
+		return p.selfID, nil
+	}
+	var connection struct {
+		AuthenticatedUser struct {
+			ID string `json:"id"`
+		} `json:"authenticatedUser"`
+	}
+	if err := p.api.do(ctx, http.MethodGet, "/_apis/connectionData", nil, &connection); err != nil {
+		return "", err
+	}
+	p.selfID = connection.AuthenticatedUser.ID
+	return p.selfID, nil
+}
+
+// Approve votes "approved" on the PR and, when a comment is given, adds it as a closed comment thread.
+func (p *azureDevOpsProvider) Approve(ctx context.Context, pr *PullRequest, comment string) error {
+	userID, err := p.authenticatedUserID(ctx)
+	if err != nil {
+		return err
+	}
+	prPath := fmt.Sprintf("%s/pullrequests/%d", p.repoPath(pr.Org, pr.Repo), pr.Number)
+
+	vote := map[string]int{"vote": 10}
+	path := fmt.Sprintf("%s/reviewers/%s?api-version=%s", prPath, url.PathEscape(userID), azureDevOpsAPIVersion)
+	if err := p.api.do(ctx, http.MethodPut, path, vote, nil); err != nil {
+		return err
+	}
+
+	if comment == "" {
+		return nil
+	}
+	thread := map[string]interface{}{
+		"comments": []map[string]interface{}{{"content": comment, "commentType": 1}},
+		"status":   "closed",
+	}
+	path = fmt.Sprintf("%s/threads?api-version=%s", prPath, azureDevOpsAPIVersion)
+	return p.api.do(ctx, http.MethodPost, path, thread, nil)
+}
+
+// azureDevOpsMergeStrategies maps renovator merge methods to Azure DevOps completion merge strategies.
+var azureDevOpsMergeStrategies = map[string]string{
+	"merge":  "noFastForward",
+	"squash": "squash",
+	"rebase": "rebase",
+}
+
+// Merge completes the PR.
+func (p *azureDevOpsProvider) Merge(ctx context.Context, pr *PullRequest, method string) error {
+	strategy, ok := azureDevOpsMergeStrategies[method]
+	if !ok {
+		return fmt.Errorf("unsupported merge method %q", method)
+	}
+	update := map[string]interface{}{
+		"status":                "completed",
+		"lastMergeSourceCommit": map[string]string{"commitId": pr.HeadSHA},
+		"completionOptions":     map[string]string{"mergeStrategy": strategy},
+	}
+	path := fmt.Sprintf("%s/pullrequests/%d?api-version=%s", p.repoPath(pr.Org, pr.Repo), pr.Number, azureDevOpsAPIVersion)
+	return p.api.do(ctx, http.MethodPatch, path, update, nil)
+}
+
+// Unapprove resets the vote of the token owner and cancels auto-complete of the PR when it is set.
+func (p *azureDevOpsProvider) Unapprove(ctx context.Context, pr *PullRequest) error {
+	userID, err := p.authenticatedUserID(ctx)
+	if err != nil {
+		return err
+	}
+	prPath := fmt.Sprintf("%s/pullrequests/%d", p.repoPath(pr.Org, pr.Repo), pr.Number)
+
+	vote := map[string]int{"vote": 0}
+	path := fmt.Sprintf("%s/reviewers/%s?api-version=%s", prPath, url.PathEscape(userID), azureDevOpsAPIVersion)
+	if err := p.api.do(ctx, http.MethodPut, path, vote, nil); err != nil {
+		return err
+	}
+
+	details, err := p.getPullRequest(ctx, pr.Org, pr.Repo, pr.Number)
+	if err != nil {
+		return err
+	}
+	if details.AutoCompleteSetBy == nil {
+		return nil
+	}
+	// auto-complete is cancelled by setting it to the empty identity
+	update := map[string]interface{}{
+		"autoCompleteSetBy": map[string]string{"id": "00000000-0000-0000-0000-000000000000"},
+	}
+	path = fmt.Sprintf("%s?api-version=%s", prPath, azureDevOpsAPIVersion)
+	return p.api.do(ctx, http.MethodPatch, path, update, nil)
+}
+
+// Close abandons the PR.
+func (p *azureDevOpsProvider) Close(ctx context.Context, pr *PullRequest) error {
+	path := fmt.Sprintf("%s/pullrequests/%d?api-version=%s", p.repoPath(pr.Org, pr.Repo), pr.Number, azureDevOpsAPIVersion)
+	return p.api.do(ctx, http.MethodPatch, path, map[string]string{"status": "abandoned"}, nil)
+}
+
+// Comment adds the comment as an active comment thread.
+func (p *azureDevOpsProvider) Comment(ctx context.Context, pr *PullRequest, body string) error {
+	thread := map[string]interface{}{
+		"comments": []map[string]interface{}{{"content": body, "commentType": 1}},
+		"status":   "active",
+	}
+	path := fmt.Sprintf("%s/pullrequests/%d/threads?api-version=%s", p.repoPath(pr.Org, pr.Repo), pr.Number,
+		azureDevOpsAPIVersion)
+	return p.api.do(ctx, http.MethodPost, path, thread, nil)
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// confirmBatch lists prs and asks once whether to process them, so that they need not be confirmed one by one.
+// It returns the PRs to process, recording the others as declined, and marks the PRs confirmed in the batch.
+// The list can be filtered, in which case only the listed PRs are processed.
+func (r *runner) confirmBatch(ctx context.Context, prs []*PullRequest) []*PullRequest {
+	var pending []*PullRequest
+	for _, pr := range prs {
+		if !r.confirmed[pr.URL] {
+			pending = append(pending, pr)
+		}
+	}
+	if len(pending) < 2 {
+		return prs
+	}
+
+	var filter listFilter
+	listed := pending
+	printList := true
+	for {
+		if printList {
+			if filter.active() {
+				fmt.Printf("\n"+msg("Matching PRs (%s):")+"\n", &filter)
+			} else {
+				fmt.Println("\n" + msg("Matching PRs:"))
+			}
+			for i, pr := range listed {
+				fmt.Printf("  %d. %s/%s#%d %s%s\n", i+1, pr.Org, pr.Repo, pr.Number, pr.Title, r.authorSuffix(pr))
+			}
+			printList = false
+		}
+		fmt.Printf(msg("Proceed with these %d PRs? [y/N/edit/each]: "), len(listed))
+		response, err := readAnswer()
+		if timedOut(err, promptDefault) {
+			if promptDefault == "approve" {
+				return r.selectBatch(prs, listed, unlisted(pending, listed), "declined by user")
+			}
+			return r.selectBatch(prs, nil, pending, "no answer")
+		} else if err != nil {
+			logReadError("input", err)
+			response = "n"
+			if quitRequested {
+				response = "q"
+			}
+		}
+		if filter.update(response) {
+			listed = r.applyListFilter(ctx, &filter, pending)
+			printList = true
+			continue
+		}
+		switch strings.ToLower(response) {
+		case "y":
+			return r.selectBatch(prs, listed, unlisted(pending, listed), "declined by user")
+		case "", "n":
+			return r.selectBatch(prs, nil, pending, "declined by user")
+		case "e", "edit":
+			selected, declined, answered := promptForPRSelection(listed)
+			reason := "declined by user"
+			if !answered {
+				reason = "no answer"
+			}
+			return r.selectBatch(prs, selected, append(declined, unlisted(pending, listed)...), reason)
+		case "each":
+			return r.selectBatch(prs, nil, unlisted(pending, listed), "declined by user")
+		case "q":
+			quitRequested = true
+			return nil
+		default:
+			fmt.Println(msg("y - Approve and merge all listed PRs"))
+			fmt.Println(msg("n - Skip all listed PRs"))
+			fmt.Println(msg("edit - Select the PRs to approve and merge"))
+			fmt.Println(msg("each - Confirm each PR separately"))
+			fmt.Println(msg("/text - List only the PRs whose repository or dependency contains text, / lists all"))
+			fmt.Println(msg("major, minor, patch - Toggle listing only PRs with these update kinds"))
+			fmt.Println(msg("passing, failing - Toggle listing only PRs with these check statuses"))
+			fmt.Println(msg("clear - Clear all filters"))
+			fmt.Println(msg("q - Stop the run"))
+		}
+	}
+}
+
+// unlisted returns the PRs of prs missing from listed.
+func unlisted(prs, listed []*PullRequest) []*PullRequest {
+	shown := map[*PullRequest]bool{}
+	for _, pr := range listed {
+		shown[pr] = true
+	}
+	var hidden []*PullRequest
+	for _, pr := range prs {
+		if !shown[pr] {
+			hidden = append(hidden, pr)
+		}
+	}
+	return hidden
+}
+
+// selectBatch marks selected as confirmed and records declined as skipped for reason, returning the PRs of prs to
+// process.
+func (r *runner) selectBatch(prs, selected, declined []*PullRequest, reason string) []*PullRequest {
+	if r.confirmed == nil {
+		r.confirmed = map[string]bool{}
+	}
+	for _, pr := range selected {
+		r.confirmed[pr.URL] = true
+	}
+	skip := map[*PullRequest]bool{}
+	for _, pr := range declined {
+		skip[pr] = true
+		r.record(pr, decisionSkipped, reason)
+	}
+	var process []*PullRequest
+	for _, pr := range prs {
+		if !skip[pr] {
+			process = append(process, pr)
+		}
+	}
+	return process
+}
+
+// promptForPRSelection asks which of prs to process as numbers and ranges of the list, e.g. 1-3,7. It reports
+// whether the selection was answered rather than defaulted after a timeout.
+func promptForPRSelection(prs []*PullRequest) (selected, declined []*PullRequest, answered bool) {
+	for {
+		fmt.Printf(msg("Select PRs to process, e.g. 1-3,7 [1-%d]: "), len(prs))
+		input, err := readAnswer()
+		if timedOut(err, promptDefault) {
+			if promptDefault == "approve" {
+				return prs, nil, false
+			}
+			return nil, prs, false
+		}
+		if err != nil {
+			logReadError("input", err)
+			return nil, prs, true
+		}
+		chosen, err := parseSelection(input, len(prs))
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		for i, pr := range prs {
+			if chosen[i] {
+				selected = append(selected, pr)
+			} else {
+				declined = append(declined, pr)
+			}
+		}
+		return selected, declined, true
+	}
+}
+
+// parseSelection parses comma separated numbers and ranges from 1 to max into the chosen zero based indexes.
+func parseSelection(input string, max int) (map[int]bool, error) {
+	chosen := map[int]bool{}
+	for _, part := range strings.Split(input, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		from, to, isRange := strings.Cut(part, "-")
+		first, err := strconv.Atoi(strings.TrimSpace(from))
+		last := first
+		if err == nil && isRange {
+			last, err = strconv.Atoi(strings.TrimSpace(to))
+		}
+		if err != nil || first < 1 || last > max || first > last {
+			return nil, fmt.Errorf(msg("invalid selection %q"), part)
+		}
+		for i := first; i <= last; i++ {
+			chosen[i-1] = true
+		}
+	}
+	return chosen, nil
+}
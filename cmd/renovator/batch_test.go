@@ -0,0 +1,33 @@
+package main
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestParseSelection(t *testing.T) {
+	tests := []struct {
+		input string
+		want  map[int]bool
+	}{
+		{"1,3", map[int]bool{0: true, 2: true}},
+		{"1, 3", map[int]bool{0: true, 2: true}},
+		{" 2 - 4 ,", map[int]bool{1: true, 2: true, 3: true}},
+		{"", map[int]bool{}},
+	}
+	for _, test := range tests {
+		got, err := parseSelection(test.input, 4)
+		if err != nil {
+			t.Errorf("parseSelection(%q): %v", test.input, err)
+			continue
+		}
+		if !maps.Equal(got, test.want) {
+			t.Errorf("parseSelection(%q) = %v, want %v", test.input, got, test.want)
+		}
+	}
+	for _, input := range []string{"0", "5", "3-2", "x", "1-"} {
+		if _, err := parseSelection(input, 4); err == nil {
+			t.Errorf("parseSelection(%q) succeeded, want an error", input)
+		}
+	}
+}
@@ -0,0 +1,272 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// bitbucketDCProvider implements Provider for Bitbucket Server and Data Center. The org of a query is the
+// project key and repositories are addressed by their slug.
+//
+// Bitbucket only lists PRs awaiting review for the token owner, so user scoped searches return the PRs in the
+// project where the owner of the token is a reviewer.
+type bitbucketDCProvider struct {
+	api *restClient
+
+	mu       sync.Mutex
+	selfSlug string
+}
+
+func newBitbucketDCProvider(baseURL string, httpClient *http.Client) *bitbucketDCProvider {
+	api := newRESTClient(baseURL, httpClient)
+	// required by Bitbucket for state changing requests made with tokens
+	api.header.Set("X-Atlassian-Token", "no-check")
+	return &bitbucketDCProvider{api: api}
+}
+
+type bitbucketUser struct {
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+type bitbucketRef struct {
+	DisplayID    string `json:"displayId"`
+	LatestCommit string `json:"latestCommit"`
+	Repository   struct {
+		Slug    string `json:"slug"`
+		Project struct {
+			Key string `json:"key"`
+		} `json:"project"`
+	} `json:"repository"`
+}
+
+type bitbucketPullRequest struct {
+	ID          int    `json:"id"`
+	Version     int    `json:"version"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	// CreatedDate is in milliseconds since the epoch
+	CreatedDate int64  `json:"createdDate"`
+	State       string `json:"state"`
+	Author      struct {
+		User bitbucketUser `json:"user"`
+	} `json:"author"`
+	FromRef bitbucketRef `json:"fromRef"`
+	ToRef   bitbucketRef `json:"toRef"`
+	Links   struct {
+		Self []struct {
+			Href string `json:"href"`
+		} `json:"self"`
+	} `json:"links"`
+}
+
+func (pr *bitbucketPullRequest) toPullRequest() *PullRequest {
+	result := &PullRequest{
+		Org:       pr.ToRef.Repository.Project.Key,
+		Repo:      pr.ToRef.Repository.Slug,
+		Number:    pr.ID,
+		Title:     pr.Title,
+		Body:      pr.Description,
+		HeadSHA:   pr.FromRef.LatestCommit,
+		Merged:    pr.State == "MERGED",
+		Closed:    pr.State == "DECLINED",
+		CreatedAt: time.UnixMilli(pr.CreatedDate),
+		HeadRef:   pr.FromRef.DisplayID,
+	}
+	if len(pr.Links.Self) > 0 {
+		result.URL = pr.Links.Self[0].Href
+	}
+	return result
+}
+
+func (pr *bitbucketPullRequest) authoredBy(author string) bool {
+	author = strings.TrimPrefix(author, "app/")
+	return strings.EqualFold(pr.Author.User.Name, author) || strings.EqualFold(pr.Author.User.Slug, author)
+}
+
+func (p *bitbucketDCProvider) prPath(project, repo string, number int) string {
+	return fmt.Sprintf("/rest/api/latest/projects/%s/repos/%s/pull-requests/%d", url.PathEscape(project), url.PathEscape(repo), number)
+}
+
+func (p *bitbucketDCProvider) SearchUpdatePRs(ctx context.Context, query SearchQuery) ([]*PullRequest, error) {
+	limit := query.PageSize
+	if limit == 0 {
+		limit = 100
+	}
+
+	var path string
+	if query.Repo != "" {
+		path = fmt.Sprintf("/rest/api/latest/projects/%s/repos/%s/pull-requests?state=OPEN&limit=%d",
+			url.PathEscape(query.Org), url.PathEscape(query.Repo), limit)
+	} else if query.User == "" {
+		return nil, fmt.Errorf("listing all pull requests of a project is not supported, a repository is required")
+	} else {
+		path = fmt.Sprintf("/rest/api/latest/dashboard/pull-requests?state=OPEN&role=REVIEWER&limit=%d", limit)
+	}
+
+	var prs []*PullRequest
+	for start := 0; ; {
+		var page struct {
+			Values        []bitbucketPullRequest `json:"values"`
+			IsLastPage    bool                   `json:"isLastPage"`
+			NextPageStart int                    `json:"nextPageStart"`
+		}
+		if err := p.api.do(ctx, http.MethodGet, fmt.Sprintf("%s&start=%d", path, start), nil, &page); err != nil {
+			return nil, err
+		}
+		for i := range page.Values {
+			pr := &page.Values[i]
+			if !pr.authoredBy(query.Author) || !strings.EqualFold(pr.ToRef.Repository.Project.Key, query.Org) {
+				continue
+			}
+			prs = append(prs, pr.toPullRequest())
+		}
+		if page.IsLastPage || page.NextPageStart <= start {
+			return prs, nil
+		}
+		start = page.NextPageStart
+	}
+}
+
+func (p *bitbucketDCProvider) getPullRequest(ctx context.Context, project, repo string, number int) (*bitbucketPullRequest, error) {
+	var pr bitbucketPullRequest
+	if err := p.api.do(ctx, http.MethodGet, p.prPath(project, repo, number), nil, &pr); err != nil {
+		return nil, err
+	}
+	return &pr, nil
+}
+
+func (p *bitbucketDCProvider) GetPR(ctx context.Context, org, repo string, number int) (*PullRequest, error) {
+	pr, err := p.getPullRequest(ctx, org, repo, number)
+	if err != nil {
+		return nil, err
+	}
+	result := pr.toPullRequest()
+
+	// merge vetoes include missing approvals, so only conflicts count against mergeability here
+	var mergeStatus struct {
+		Conflicted bool `json:"conflicted"`
+	}
+	if err := p.api.do(ctx, http.MethodGet, p.prPath(org, repo, number)+"/merge", nil, &mergeStatus); err != nil {
+		return nil, err
+	}
+	result.Mergeable = pr.State == "OPEN" && !mergeStatus.Conflicted
+	return result, nil
+}
+
+// EvaluateChecks reads all pages of build statuses, so that a failing status on a later page is not missed.
+func (p *bitbucketDCProvider) EvaluateChecks(ctx context.Context, pr *PullRequest) (bool, error) {
+	for start := 0; ; {
+		var statuses struct {
+			Values []struct {
+				State string `json:"state"`
+			} `json:"values"`
+			IsLastPage    bool `json:"isLastPage"`
+			NextPageStart int  `json:"nextPageStart"`
+		}
+		path := fmt.Sprintf("/rest/build-status/latest/commits/%s?limit=100&start=%d", url.PathEscape(pr.HeadSHA), start)
+		if err := p.api.do(ctx, http.MethodGet, path, nil, &statuses); err != nil {
+			return false, err
+		}
+		for _, status := range statuses.Values {
+			if status.State != "SUCCESSFUL" {
+				return false, nil
+			}
+		}
+		if statuses.IsLastPage || statuses.NextPageStart <= start {
+			return true, nil
+		}
+		start = statuses.NextPageStart
+	}
+}
+
+// authenticatedUserSlug returns the user slug of the token owner, needed to update the participant status.
+func (p *bitbucketDCProvider) authenticatedUserSlug(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.selfSlug != "" {
+		return p.selfSlug, nil
+	}
+	name, err := p.api.send(ctx, http.MethodGet, "/plugins/servlet/applinks/whoami", nil)
+	if err != nil {
+		return "", err
+	}
+	var user bitbucketUser
+	path := fmt.Sprintf("/rest/api/latest/users/%s", url.PathEscape(strings.TrimSpace(string(name))))
+	if err := p.api.do(ctx, http.MethodGet, path, nil, &user); err != nil {
+		return "", err
+	}
+	p.selfSlug = user.Slug
+	return p.selfSlug, nil
+}
+
+func (p *bitbucketDCProvider) Approve(ctx context.Context, pr *PullRequest, comment string) error {
+	slug, err := p.authenticatedUserSlug(ctx)
+	if err != nil {
+		return err
+	}
+	path := p.prPath(pr.Org, pr.Repo, pr.Number)
+
+	if comment != "" {
+		if err := p.api.do(ctx, http.MethodPost, path+"/comments", map[string]string{"text": comment}, nil); err != nil {
+			return err
+		}
+	}
+	participant := map[string]string{"status": "APPROVED"}
+	return p.api.do(ctx, http.MethodPut, path+"/participants/"+url.PathEscape(slug), participant, nil)
+}
+
+// bitbucketMergeStrategies maps renovator merge methods to Bitbucket merge strategy IDs.
+var bitbucketMergeStrategies = map[string]string{
+	"merge":  "no-ff",
+	"squash": "squash",
+	"rebase": "rebase-ff-only",
+}
+
+func (p *bitbucketDCProvider) Merge(ctx context.Context, pr *PullRequest, method string) error {
+	strategy, ok := bitbucketMergeStrategies[method]
+	if !ok {
+		return fmt.Errorf("unsupported merge method %q", method)
+	}
+	// merging requires the current version of the PR, which changes with every approval
+	current, err := p.getPullRequest(ctx, pr.Org, pr.Repo, pr.Number)
+	if err != nil {
+		return err
+	}
+	// commits pushed since the head was checked would be merged unseen, and pushing bumps the version merged at
+	if pr.HeadSHA != "" && current.FromRef.LatestCommit != pr.HeadSHA {
+		return fmt.Errorf("head of the PR moved from %s to %s", pr.HeadSHA, current.FromRef.LatestCommit)
+	}
+	path := fmt.Sprintf("%s/merge?version=%d", p.prPath(pr.Org, pr.Repo, pr.Number), current.Version)
+	return p.api.do(ctx, http.MethodPost, path, map[string]string{"strategyId": strategy}, nil)
+}
+
+func (p *bitbucketDCProvider) Unapprove(ctx context.Context, pr *PullRequest) error {
+	slug, err := p.authenticatedUserSlug(ctx)
+	if err != nil {
+		return err
+	}
+	path := p.prPath(pr.Org, pr.Repo, pr.Number) + "/participants/" + url.PathEscape(slug)
+	return p.api.do(ctx, http.MethodPut, path, map[string]string{"status": "UNAPPROVED"}, nil)
+}
+
+// Close declines the PR.
+func (p *bitbucketDCProvider) Close(ctx context.Context, pr *PullRequest) error {
+	// declining requires the current version of the PR like merging does
+	current, err := p.getPullRequest(ctx, pr.Org, pr.Repo, pr.Number)
+	if err != nil {
+		return err
+	}
+	path := fmt.Sprintf("%s/decline?version=%d", p.prPath(pr.Org, pr.Repo, pr.Number), current.Version)
+	return p.api.do(ctx, http.MethodPost, path, map[string]string{}, nil)
+}
+
+func (p *bitbucketDCProvider) Comment(ctx context.Context, pr *PullRequest, body string) error {
+	path := p.prPath(pr.Org, pr.Repo, pr.Number) + "/comments"
+	return p.api.do(ctx, http.MethodPost, path, map[string]string{"text": body}, nil)
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+)
+
+// checkRunsProvider is implemented by providers that can list the state of the checks of the head of a PR.
+type checkRunsProvider interface {
+	// CheckRuns returns the conclusion, or the status while running, of each check of the head of pr by name.
+	CheckRuns(ctx context.Context, pr *PullRequest) (map[string]string, error)
+}
+
+// requirement is a branch protection requirement a PR does not satisfy and what to do about it.
+type requirement struct {
+	unmet  string
+	action string
+}
+
+// explainBlocked prints the branch protection requirements pr does not satisfy and the next action for each, so that
+// a failed merge says more than the API error, and reports whether there are any. Providers that cannot tell print
+// nothing.
+func (r *runner) explainBlocked(ctx context.Context, pr *PullRequest) bool {
+	reviews, ok := r.provider.(reviewStatusProvider)
+	if !ok {
+		return false
+	}
+	status, err := reviews.ReviewStatus(ctx, pr)
+	if err != nil {
+		verbosef("Error reading the branch protection of %s: %v\n", pr.URL, err)
+		return false
+	}
+	if status.Protection == nil {
+		return false
+	}
+	var checks map[string]string
+	if provider, ok := r.provider.(checkRunsProvider); ok && len(status.Protection.RequiredChecks) > 0 {
+		if checks, err = provider.CheckRuns(ctx, pr); err != nil {
+			verbosef("Error reading the checks of %s: %v\n", pr.URL, err)
+			return false
+		}
+	}
+
+	threads := -1
+	if status.Protection.RequiresConversationResolution {
+		threads = r.checkThreads(ctx, pr)
+	}
+	unmet := unmetRequirements(status, checks, threads)
+	if len(unmet) == 0 {
+		log.Printf("No unmet branch protection requirement found, update the branch of the PR or check the rulesets of the repository")
+		return false
+	}
+	log.Printf("The merge is blocked by branch protection:")
+	for _, req := range unmet {
+		log.Printf("  - %s: %s", req.unmet, req.action)
+	}
+	return true
+}
+
+// unmetRequirements compares the protection of the base branch to the approvals, the check states and the number
+// of unresolved review threads of a PR, which is -1 when unknown.
+func unmetRequirements(status reviewStatus, checks map[string]string, threads int) []requirement {
+	protection := status.Protection
+	var unmet []requirement
+	for _, name := range protection.RequiredChecks {
+		switch state := checks[name]; state {
+		case "success", "skipped", "neutral":
+		case "":
+			unmet = append(unmet, requirement{fmt.Sprintf("required check %s has not run", name),
+				"make sure its workflow runs for the PR branch, or re-run it"})
+		case "queued", "in_progress", "pending":
+			unmet = append(unmet, requirement{fmt.Sprintf("required check %s is %s", name, state),
+				"wait for it to finish, e.g. with -retry"})
+		default:
+			unmet = append(unmet, requirement{fmt.Sprintf("required check %s concluded %s", name, state),
+				"fix the failure or re-run the check"})
+		}
+	}
+	if status.Approvals < protection.RequiredApprovals {
+		unmet = append(unmet, requirement{
+			fmt.Sprintf("%d of %d required approvals", status.Approvals, protection.RequiredApprovals),
+			"ask a code owner or teammate to approve, e.g. with triage reviewers"})
+	}
+	switch {
+	case protection.RequiresConversationResolution && threads > 0:
+		unmet = append(unmet, requirement{fmt.Sprintf("%d review threads are not resolved", threads),
+			"resolve them, or with -resolve-bot-threads those started by bots"})
+	case protection.RequiresConversationResolution && threads < 0:
+		unmet = append(unmet, requirement{"conversations must be resolved before merging",
+			"resolve the open review threads of the PR"})
+	}
+	return unmet
+}
+
+// missingApprovals returns how many more approvals the protection of the base branch requires for pr, counting the
+// approval renovator has given, or 0 when enough are given or the provider cannot tell.
+func (r *runner) missingApprovals(ctx context.Context, pr *PullRequest) int {
+	reviews, ok := r.provider.(reviewStatusProvider)
+	if !ok {
+		return 0
+	}
+	status, err := reviews.ReviewStatus(ctx, pr)
+	if err != nil {
+		verbosef("Error reading the approvals of %s: %v\n", pr.URL, err)
+		return 0
+	}
+	if status.Protection == nil {
+		return 0
+	}
+	return max(status.Protection.RequiredApprovals-status.Approvals, 0)
+}
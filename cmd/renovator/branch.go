@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"regexp"
+	"strings"
+)
+
+var (
+	// branchPrefixPattern matches the prefix of update branches before the dependency: the branch prefix of
+	// Renovate such as renovate/, optionally followed by major-, or dependabot/<ecosystem>/ of Dependabot
+	branchPrefixPattern = regexp.MustCompile(`^(?:dependabot/[^/]+/|[^/]+/(?:major-)?)`)
+	// branchVersionPattern matches the version suffix of update branches such as -4.x, -v1.22.x or -4.17.21
+	branchVersionPattern = regexp.MustCompile(`-v?\d+(?:\.(?:\d+|x))*$`)
+)
+
+// branchDependency returns the dependency an update branch such as renovate/lodash-4.x is named after, as
+// sanitized by Renovate, or "" for branches not named after a dependency. Unlike titles, branch names are the same
+// across repositories whatever their commit message settings.
+func branchDependency(ref string) string {
+	ref = strings.TrimPrefix(ref, "refs/heads/")
+	prefix := branchPrefixPattern.FindString(ref)
+	if prefix == "" {
+		return ""
+	}
+	return sanitizeDependency(branchVersionPattern.ReplaceAllString(ref[len(prefix):], ""))
+}
+
+// sanitizeDependency converts a dependency name such as @types/node or github.com/foo/bar to the form Renovate
+// names branches after, node or github.com-foo-bar.
+func sanitizeDependency(name string) string {
+	name = strings.Replace(name, "@types/", "", 1)
+	name = strings.Replace(name, "@", "", 1)
+	name = strings.Join(strings.Fields(strings.ReplaceAll(name, "/", " ")), "-")
+	return strings.ToLower(name)
+}
+
+// dependencyKey returns what PRs are matched to -d and grouped by: their title or, with -match branch, the
+// dependency their branch is named after. Branches missing from search results are read from the PR.
+func (r *runner) dependencyKey(ctx context.Context, pr *PullRequest) string {
+	if r.opts.match != "branch" {
+		return pr.Title
+	}
+	if pr.HeadRef == "" {
+		details, err := r.provider.GetPR(ctx, pr.Org, pr.Repo, pr.Number)
+		if err != nil {
+			verbosef("Error reading the branch of %s: %v\n", pr.URL, err)
+			return ""
+		}
+		pr.HeadRef = details.HeadRef
+	}
+	return branchDependency(pr.HeadRef)
+}
+
+// matchesDependency reports whether pr updates the dependency given with -d. Titles match when they are the same or
+// describe the same update in another convention.
+func (r *runner) matchesDependency(ctx context.Context, pr *PullRequest) bool {
+	if r.opts.match != "branch" {
+		return pr.Title == r.opts.dependency || sameDependency(pr.Title, r.opts.dependency)
+	}
+	return r.dependencyKey(ctx, pr) == sanitizeDependency(r.opts.dependency)
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+// repoLister is implemented by providers that can list the active repositories of an org.
+type repoLister interface {
+	ListRepos(ctx context.Context, org string) ([]string, error)
+}
+
+// campaignRepo is where a repository stands in the upgrade campaign of a dependency.
+type campaignRepo struct {
+	Org  string `json:"org"`
+	Repo string `json:"repo"`
+	// Status is merged, pending for an open PR or none when the repository has no PR for the dependency.
+	Status string `json:"status"`
+	URL    string `json:"url,omitempty"`
+	To     string `json:"to,omitempty"`
+	// Since is when the PR was merged or opened.
+	Since time.Time `json:"since,omitzero"`
+}
+
+// campaign returns where the repositories of the target stand in upgrading dependency: merged according to the
+// state records, pending with an open PR, or without a PR yet when the provider can list repositories.
+func (r *runner) campaign(ctx context.Context, dependency string, records []stateRecord) ([]campaignRepo, error) {
+	repos := map[string]campaignRepo{}
+	for _, record := range records {
+		if record.Org == r.opts.org && record.Decision == decisionMerged && record.Update.Dependency == dependency {
+			repos[record.Repo] = campaignRepo{
+				Org: record.Org, Repo: record.Repo, Status: "merged", URL: record.URL, To: record.Update.To,
+				Since: record.Time,
+			}
+		}
+	}
+
+	query := SearchQuery{Org: r.opts.org, User: r.opts.user, Repo: r.opts.repo, Author: r.opts.author}
+	prs, err := r.searchPRs(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("error searching PRs: %w", err)
+	}
+	for _, pr := range prs {
+		// an open PR, e.g. for a newer version, takes precedence over an earlier merge
+		if u := parseUpdate(pr); u.Dependency == dependency {
+			repos[pr.Repo] = campaignRepo{Org: pr.Org, Repo: pr.Repo, Status: "pending", URL: pr.URL, To: u.To, Since: pr.CreatedAt}
+		}
+	}
+
+	if lister, ok := r.provider.(repoLister); ok && r.opts.repo == "" {
+		names, err := lister.ListRepos(ctx, r.opts.org)
+		if err != nil {
+			return nil, fmt.Errorf("error listing repositories: %w", err)
+		}
+		for _, name := range names {
+			if _, ok := repos[name]; !ok {
+				repos[name] = campaignRepo{Org: r.opts.org, Repo: name, Status: "none"}
+			}
+		}
+	}
+
+	result := make([]campaignRepo, 0, len(repos))
+	for _, repo := range repos {
+		result = append(result, repo)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Status != result[j].Status {
+			return campaignOrder[result[i].Status] < campaignOrder[result[j].Status]
+		}
+		return result[i].Repo < result[j].Repo
+	})
+	return result, nil
+}
+
+// campaignOrder lists merged repositories first and those without a PR last.
+var campaignOrder = map[string]int{"merged": 0, "pending": 1, "none": 2}
+
+// writeCampaign writes the percentage of repositories that merged the dependency followed by a table of all
+// repositories, or JSON.
+func writeCampaign(w io.Writer, dependency string, repos []campaignRepo, output string) error {
+	merged := 0
+	for _, repo := range repos {
+		if repo.Status == "merged" {
+			merged++
+		}
+	}
+	percent := 0.0
+	if len(repos) > 0 {
+		percent = float64(merged) * 100 / float64(len(repos))
+	}
+
+	if output == "json" {
+		if repos == nil {
+			repos = []campaignRepo{}
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(struct {
+			Dependency string         `json:"dependency"`
+			Merged     int            `json:"merged"`
+			Total      int            `json:"total"`
+			Percent    float64        `json:"percent"`
+			Repos      []campaignRepo `json:"repos"`
+		}{dependency, merged, len(repos), percent, repos})
+	}
+
+	fmt.Fprintf(w, msg("%s: %d of %d repos merged (%.0f%%)")+"\n\n", dependency, merged, len(repos), percent)
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, msg("REPO\tSTATUS\tVERSION\tSINCE\tURL"))
+	for _, repo := range repos {
+		since := ""
+		if !repo.Since.IsZero() {
+			since = repo.Since.Local().Format(time.DateOnly)
+		}
+		fmt.Fprintf(table, "%s/%s\t%s\t%s\t%s\t%s\n", repo.Org, repo.Repo, repo.Status, repo.To, since, repo.URL)
+	}
+	return table.Flush()
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v50/github"
+	"golang.org/x/oauth2"
+)
+
+const (
+	// changelogMaxVersions and changelogMaxLines condense the changelog shown before the prompt.
+	changelogMaxVersions = 10
+	changelogMaxLines    = 8
+)
+
+var (
+	// sourceRepoPattern matches the GitHub source link of the package column in Renovate PR bodies such as
+	// "[lodash](https://lodash.com/) ([source](https://github.com/lodash/lodash))".
+	sourceRepoPattern = regexp.MustCompile(`\[source\]\(https://github\.com/([\w.-]+)/([\w.-]+?)(?:\.git)?(?:/[^)]*)?\)`)
+	// changelogHeadingPattern matches the version headings of CHANGELOG.md such as "## [1.2.3] - 2024-01-01" or
+	// "# v1.2.3".
+	changelogHeadingPattern = regexp.MustCompile(`^#{1,3}\s+\[?v?(\d+(?:\.\d+)*[\w.+-]*)\]?`)
+)
+
+// changelogEntry is what changed in a single version.
+type changelogEntry struct {
+	Version string
+	Lines   []string
+}
+
+// showChangelog prints the upstream releases, or the CHANGELOG.md, between the versions of the update of pr when
+// the Renovate description has no release notes. Failures are only shown with -v as the changelog is a nicety.
+func (r *runner) showChangelog(ctx context.Context, pr *PullRequest) {
+	if strings.Contains(pr.Body, "### Release Notes") {
+		return
+	}
+	u := parseUpdate(pr)
+	match := sourceRepoPattern.FindStringSubmatch(pr.Body)
+	if u.From == "" || u.To == "" || match == nil {
+		return
+	}
+	if r.upstream == nil {
+		httpClient := http.DefaultClient
+		if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+			httpClient = bearerClient(oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token}), http.DefaultTransport)
+		}
+		r.upstream = github.NewClient(httpClient)
+	}
+	owner, repo := match[1], match[2]
+	entries, err := fetchChangelog(ctx, r.upstream, owner, repo, u.From, u.To)
+	if err != nil {
+		verbosef("Error fetching changelog of %s/%s: %v\n", owner, repo, err)
+		return
+	}
+	if len(entries) == 0 {
+		return
+	}
+
+	fmt.Printf(msg("Changelog of %s/%s from %s to %s:")+"\n", owner, repo, u.From, u.To)
+	for i, entry := range entries {
+		if i == changelogMaxVersions {
+			fmt.Printf("  "+msg("... and %d older versions")+"\n", len(entries)-i)
+			break
+		}
+		fmt.Printf("  %s\n", entry.Version)
+		for j, line := range entry.Lines {
+			if j == changelogMaxLines {
+				fmt.Printf("    "+msg("... %d more lines")+"\n", len(entry.Lines)-j)
+				break
+			}
+			fmt.Printf("    %s\n", line)
+		}
+	}
+}
+
+// fetchChangelog returns the entries of the versions after from up to to, newest first, from the GitHub releases
+// of the repository or, when it has none, from its CHANGELOG.md.
+func fetchChangelog(ctx context.Context, client *github.Client, owner, repo, from, to string) ([]changelogEntry, error) {
+	releases, _, err := client.Repositories.ListReleases(ctx, owner, repo, &github.ListOptions{PerPage: 100})
+	if err != nil {
+		return nil, err
+	}
+	var entries []changelogEntry
+	for _, release := range releases {
+		tag := release.GetTagName()
+		// tags of monorepos are prefixed with the package, e.g. @babel/core@7.24.0
+		version := tag[strings.LastIndex(tag, "@")+1:]
+		if release.GetDraft() || !inVersionRange(version, from, to) {
+			continue
+		}
+		entries = append(entries, changelogEntry{Version: tag, Lines: changelogLines(release.GetBody())})
+	}
+	if len(releases) > 0 {
+		return entries, nil
+	}
+
+	file, _, resp, err := client.Repositories.GetContents(ctx, owner, repo, "CHANGELOG.md", nil)
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if file == nil {
+		return nil, nil
+	}
+	content, err := file.GetContent()
+	if err != nil {
+		return nil, err
+	}
+	return parseChangelog(content, from, to), nil
+}
+
+// parseChangelog returns the sections of a CHANGELOG.md of the versions after from up to to in the order listed.
+func parseChangelog(content, from, to string) []changelogEntry {
+	var entries []changelogEntry
+	var current *changelogEntry
+	var section []string
+	flush := func() {
+		if current != nil {
+			current.Lines = changelogLines(strings.Join(section, "\n"))
+			entries = append(entries, *current)
+		}
+		current, section = nil, nil
+	}
+	for _, line := range strings.Split(content, "\n") {
+		if match := changelogHeadingPattern.FindStringSubmatch(line); match != nil {
+			flush()
+			if inVersionRange(match[1], from, to) {
+				current = &changelogEntry{Version: match[1]}
+			}
+			continue
+		}
+		if current != nil {
+			section = append(section, line)
+		}
+	}
+	flush()
+	return entries
+}
+
+// changelogLines returns the non-empty lines of release notes, dropping markdown headings and comments.
+func changelogLines(notes string) []string {
+	var lines []string
+	for _, line := range strings.Split(notes, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "<!--") {
+			continue
+		}
+		lines = append(lines, strings.TrimSpace(strings.TrimLeft(line, "#")))
+	}
+	return lines
+}
+
+// inVersionRange reports whether version is after from and at most to.
+func inVersionRange(version, from, to string) bool {
+	if len(versionComponents(version)) == 0 {
+		return false
+	}
+	return compareVersions(version, from) > 0 && compareVersions(version, to) <= 0
+}
+
+// compareVersions compares the numeric components of two versions, returning -1, 0 or 1.
+func compareVersions(a, b string) int {
+	ca, cb := versionComponents(a), versionComponents(b)
+	for i := 0; i < max(len(ca), len(cb)); i++ {
+		var na, nb int
+		if i < len(ca) {
+			na, _ = strconv.Atoi(ca[i])
+		}
+		if i < len(cb) {
+			nb, _ = strconv.Atoi(cb[i])
+		}
+		if na != nb {
+			if na < nb {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+)
+
+// closeUnwantedPR has the version of pr ignored with the close comment instead of merging it, asking first unless
+// confirmed.
+func (r *runner) closeUnwantedPR(ctx context.Context, pr *PullRequest) {
+	r.printf("\n"+msg("Processing PR: %s")+"\n", pr.Title)
+	r.printf(msg("Repo URL: %s")+"\n", pr.URL)
+
+	if !r.opts.yes && r.needsConfirming(pr) {
+		confirmed, answered := confirmClose(pr.Title)
+		if quitRequested {
+			r.printf("%s\n", msg("Stopping the run"))
+			return
+		}
+		if !confirmed {
+			r.printf(paint(decisionSkipped, msg("Skipping PR: %s"))+"\n", pr.Title)
+			if answered {
+				r.record(pr, decisionSkipped, "declined by user")
+			} else {
+				r.record(pr, decisionSkipped, "no answer")
+			}
+			return
+		}
+	}
+	r.ignoreVersion(ctx, pr, r.opts.closeComment)
+}
+
+// closePR comments on pr, unless comment is empty, and closes it.
+func (r *runner) closePR(ctx context.Context, pr *PullRequest, comment string) {
+	if comment != "" {
+		if err := r.provider.Comment(ctx, pr, comment); err != nil {
+			log.Printf(paint(decisionFailed, "Error commenting on PR: %v"), err)
+			r.record(pr, decisionFailed, err.Error())
+			return
+		}
+	}
+	if err := r.provider.Close(ctx, pr); err != nil {
+		log.Printf(paint(decisionFailed, "Error closing PR: %v"), err)
+		r.record(pr, decisionFailed, err.Error())
+		return
+	}
+	r.printf(paint(decisionSkipped, msg("Closed PR: %s"))+"\n", pr.Title)
+	r.record(pr, decisionSkipped, "closed")
+}
+
+// confirmClose asks whether to close the PR, reporting whether it was answered rather than defaulted after a timeout.
+func confirmClose(prTitle string) (confirmed, answered bool) {
+	fmt.Printf(msg("Close PR '%s'? [y/N/q]: "), prTitle)
+	response, err := readAnswer()
+	if timedOut(err, promptDefault) {
+		return promptDefault == "approve", false
+	}
+	if err != nil {
+		logReadError("input", err)
+		return false, true
+	}
+	switch response {
+	case "y", "Y":
+		return true, true
+	case "q", "Q":
+		quitRequested = true
+	}
+	return false, true
+}
+
+func promptForCloseComment() string {
+	fmt.Print(msg("Enter comment to close the PR with: "))
+	comment, err := readAnswer()
+	if err != nil {
+		logReadError("comment", err)
+		return ""
+	}
+	return comment
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"os"
+
+	"golang.org/x/term"
+)
+
+const (
+	ansiReset  = "\033[0m"
+	ansiRed    = "\033[31m"
+	ansiGreen  = "\033[32m"
+	ansiYellow = "\033[33m"
+)
+
+// colorEnabled is set by setupColor when output should be colored.
+var colorEnabled bool
+
+// setupColor enables colored output for terminals unless disabled with -no-color or the NO_COLOR environment
+// variable (https://no-color.org).
+func setupColor(noColor bool) {
+	colorEnabled = !noColor && os.Getenv("NO_COLOR") == "" && term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// paint colors text by decision: green for merged, yellow for skipped and red for failed.
+func paint(d decision, text string) string {
+	if !colorEnabled {
+		return text
+	}
+	color := map[decision]string{decisionMerged: ansiGreen, decisionSkipped: ansiYellow, decisionFailed: ansiRed}[d]
+	if color == "" {
+		return text
+	}
+	return color + text + ansiReset
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"text/template"
+)
+
+// commitTemplateConfig are Go templates of the commit title and body of squash merges, executed with templateData.
+type commitTemplateConfig struct {
+	// Title is e.g. "chore(deps): update {{.Dependency}} to {{.To}} (#{{.Number}})".
+	Title string `yaml:"title"`
+	// Body is left to the provider when empty.
+	Body string `yaml:"body"`
+}
+
+// templateData is what squash commit and approval comment templates are executed with for a PR.
+type templateData struct {
+	Org        string
+	Repo       string
+	Number     int
+	Title      string
+	URL        string
+	Dependency string
+	From       string
+	To         string
+	// Kind is major, minor or patch, or "" when the versions are not numeric.
+	Kind string
+	// Author is the author of -a the PR was found for.
+	Author string
+}
+
+func newTemplateData(pr *PullRequest) templateData {
+	u := parseUpdate(pr)
+	return templateData{
+		Org: pr.Org, Repo: pr.Repo, Number: pr.Number, Title: pr.Title, URL: pr.URL,
+		Dependency: u.Dependency, From: u.From, To: u.To, Kind: u.kind(), Author: pr.Author,
+	}
+}
+
+// commitTemplate renders the commit messages of squash merges.
+type commitTemplate struct {
+	title, body *template.Template
+}
+
+// newCommitTemplate parses the templates of c, trying them out so that unknown fields are reported before anything
+// is merged. It returns nil for a nil c.
+func newCommitTemplate(c *commitTemplateConfig) (*commitTemplate, error) {
+	if c == nil {
+		return nil, nil
+	}
+	if c.Title == "" {
+		return nil, fmt.Errorf("a commit title template is required")
+	}
+	t := &commitTemplate{}
+	var err error
+	if t.title, err = parseCommitTemplate("title", c.Title); err != nil {
+		return nil, err
+	}
+	if c.Body != "" {
+		if t.body, err = parseCommitTemplate("body", c.Body); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+func parseCommitTemplate(name, text string) (*template.Template, error) {
+	tmpl, err := template.New(name).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parsing commit %s template: %w", name, err)
+	}
+	if err := tmpl.Execute(io.Discard, templateData{}); err != nil {
+		return nil, fmt.Errorf("trying commit %s template: %w", name, err)
+	}
+	return tmpl, nil
+}
+
+// render returns the commit title and body of pr. The body is "" when no body template is configured.
+func (t *commitTemplate) render(pr *PullRequest) (title, body string, err error) {
+	data := newTemplateData(pr)
+	var b bytes.Buffer
+	if err := t.title.Execute(&b, data); err != nil {
+		return "", "", err
+	}
+	title = b.String()
+	if t.body != nil {
+		b.Reset()
+		if err := t.body.Execute(&b, data); err != nil {
+			return "", "", err
+		}
+		body = b.String()
+	}
+	return title, body, nil
+}
+
+// commitMessageMerger is implemented by providers that can merge with a given commit title and body.
+type commitMessageMerger interface {
+	// MergeWithMessage merges pr like Merge, using title and, when not "", body for the commit.
+	MergeWithMessage(ctx context.Context, pr *PullRequest, method, title, body string) error
+}
+
+// merge merges pr, squashing with the commit message rendered from the squash commit template of the repository, or
+// the configured one, when there is one and the provider supports it.
+func (r *runner) merge(ctx context.Context, pr *PullRequest, method string, repoCfg *repoConfig) error {
+	tmpl := r.squashCommit
+	if repoCfg.squashCommit != nil {
+		tmpl = repoCfg.squashCommit
+	}
+	if method != "squash" || tmpl == nil {
+		return r.provider.Merge(ctx, pr, method)
+	}
+	merger, ok := r.provider.(commitMessageMerger)
+	if !ok {
+		verbosef("Commit message templates are not supported by the provider of %s\n", r.target)
+		return r.provider.Merge(ctx, pr, method)
+	}
+	title, body, err := tmpl.render(pr)
+	if err != nil {
+		return fmt.Errorf("rendering commit message: %w", err)
+	}
+	verbosef("Squashing as: %s\n", title)
+	return merger.MergeWithMessage(ctx, pr, method, title, body)
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// config is the content of the YAML configuration file given with -config.
+type config struct {
+	// Targets are processed one after another within a single run.
+	Targets []targetConfig `yaml:"targets"`
+	// Credentials map an org, or a provider and org as <provider>/<org>, to the token used for targets in it
+	// that do not configure their own.
+	Credentials map[string]tokenConfig `yaml:"credentials"`
+	// Freezes are deployment freezes during which nothing is merged.
+	Freezes freezePeriods `yaml:"freezes"`
+	// FreezeCalendar is the URL of an iCalendar feed whose events are deployment freezes.
+	FreezeCalendar string `yaml:"freezeCalendar"`
+	// Notifications are sent the results of each run.
+	Notifications notificationsConfig `yaml:"notifications"`
+	// Jira is where merges are recorded.
+	Jira *jiraConfig `yaml:"jira"`
+	// Alerting raises alerts when PRs keep failing to merge.
+	Alerting *alertingConfig `yaml:"alerting"`
+	// Plugins are consulted before approving and merging each PR.
+	Plugins []pluginConfig `yaml:"plugins"`
+	// Policies decide per PR whether it is merged, prompted for or skipped. The first matching policy applies.
+	Policies []policyConfig `yaml:"policies"`
+	// OPA is a Rego policy deciding on PRs ahead of Policies.
+	OPA *opaConfig `yaml:"opa"`
+	// Triage requests reviews for and comments on PRs that are not merged automatically.
+	Triage *triageConfig `yaml:"triage"`
+	// DepsDev checks the versions of updates on deps.dev, requiring confirmation when the license changes.
+	DepsDev *depsDevConfig `yaml:"depsDev"`
+	// SquashCommit templates the commit messages of squash merges.
+	SquashCommit *commitTemplateConfig `yaml:"squashCommit"`
+	// Planning attaches merged PRs to a milestone or project board.
+	Planning *planningConfig `yaml:"planning"`
+	// Escalation labels and assigns PRs that keep failing their checks long after they were opened.
+	Escalation *escalationConfig `yaml:"escalation"`
+	// RenovateTrigger asks Renovate to run again for repositories whose PRs conflict or go stale.
+	RenovateTrigger *renovateTriggerConfig `yaml:"renovateTrigger"`
+	// Profiles are named sets of settings, e.g. work and oss, selected with -profile. They override the settings
+	// outside of profiles.
+	Profiles map[string]config `yaml:"profiles"`
+}
+
+// targetConfig is a provider and scope to process. Empty fields default to the corresponding command line flags.
+type targetConfig struct {
+	Provider    string `yaml:"provider"`
+	BaseURL     string `yaml:"baseUrl"`
+	Org         string `yaml:"org"`
+	User        string `yaml:"user"`
+	Repo        string `yaml:"repo"`
+	Author      string `yaml:"author"`
+	OrgRole     string `yaml:"orgRole"`
+	tokenConfig `yaml:",inline"`
+}
+
+// tokenConfig is where to read a token from. At most one source is expected to be set.
+type tokenConfig struct {
+	// TokenVariable is the environment variable holding the token.
+	TokenVariable string `yaml:"tokenVariable"`
+	// TokenKeyring is the OS keychain account holding the token.
+	TokenKeyring string `yaml:"tokenKeyring"`
+	// TokenFile is the path of a file holding the token.
+	TokenFile string `yaml:"tokenFile"`
+	// TokenVault is the Vault KV path holding the token in TokenVaultField (default token).
+	TokenVault      string `yaml:"tokenVault"`
+	TokenVaultField string `yaml:"tokenVaultField"`
+	// TokenAWSSecret is the AWS Secrets Manager secret holding the token.
+	TokenAWSSecret string `yaml:"tokenAwsSecret"`
+	// TokenAWSParameter is the AWS SSM parameter holding the token.
+	TokenAWSParameter string `yaml:"tokenAwsParameter"`
+}
+
+func loadConfig(path string) (*config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cfg config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// withProfile returns the config with the settings of the named profile applied.
+func (c *config) withProfile(name string) (*config, error) {
+	profile, ok := c.Profiles[name]
+	if !ok {
+		names := make([]string, 0, len(c.Profiles))
+		for n := range c.Profiles {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("unknown profile %q, expected one of: %s", name, strings.Join(names, ", "))
+	}
+	base := *c
+	base.Profiles = nil
+	profile.Profiles = nil
+	merged := mergeConfig(base, profile)
+	return &merged, nil
+}
+
+// credentialsFor returns the credentials mapped to the org of the target, if any.
+func (c *config) credentialsFor(t targetConfig) (tokenConfig, bool) {
+	if credentials, ok := c.Credentials[t.Provider+"/"+t.Org]; ok {
+		return credentials, true
+	}
+	credentials, ok := c.Credentials[t.Org]
+	return credentials, ok
+}
+
+// resolveTarget fills empty fields of t from defaults, taking the token from the credentials mapped to its org
+// when the target has no token source of its own.
+func (c *config) resolveTarget(t, defaults targetConfig) targetConfig {
+	t = t.withDefaults(defaults)
+	if !t.hasTokenSource() || t.tokenConfig == defaults.tokenConfig {
+		if credentials, ok := c.credentialsFor(t); ok && credentials.hasTokenSource() {
+			vaultField := t.TokenVaultField
+			t.tokenConfig = credentials
+			if t.TokenVaultField == "" {
+				t.TokenVaultField = vaultField
+			}
+		}
+	}
+	return t
+}
+
+// withDefaults fills empty fields of t from defaults.
+func (t targetConfig) withDefaults(defaults targetConfig) targetConfig {
+	if t.Provider == "" {
+		t.Provider = defaults.Provider
+	}
+	if t.BaseURL == "" && t.Provider == defaults.Provider {
+		t.BaseURL = defaults.BaseURL
+	}
+	if t.Org == "" {
+		t.Org = defaults.Org
+	}
+	if t.User == "" && t.Repo == "" {
+		t.User = defaults.User
+		t.Repo = defaults.Repo
+	}
+	if t.Author == "" {
+		t.Author = defaults.Author
+	}
+	if t.OrgRole == "" {
+		t.OrgRole = defaults.OrgRole
+	}
+	if !t.hasTokenSource() {
+		t.TokenVariable = defaults.TokenVariable
+		t.TokenKeyring = defaults.TokenKeyring
+		t.TokenFile = defaults.TokenFile
+		t.TokenVault = defaults.TokenVault
+		t.TokenAWSSecret = defaults.TokenAWSSecret
+		t.TokenAWSParameter = defaults.TokenAWSParameter
+	}
+	if t.TokenVaultField == "" {
+		t.TokenVaultField = defaults.TokenVaultField
+	}
+	return t
+}
+
+func (t tokenConfig) hasTokenSource() bool {
+	return t.TokenVariable != "" || t.TokenKeyring != "" || t.TokenFile != "" || t.TokenVault != "" ||
+		t.TokenAWSSecret != "" || t.TokenAWSParameter != ""
+}
+
+// validate checks that t names an org and, when requireUser is set, the user or the repository to process.
+func (t targetConfig) validate(requireUser bool) error {
+	if t.Org == "" {
+		return fmt.Errorf("org is required")
+	}
+	if requireUser && t.User == "" && t.Repo == "" {
+		return fmt.Errorf("either user or repo is required")
+	}
+	if t.OrgRole != "" && t.OrgRole != "member" && t.OrgRole != "admin" {
+		return fmt.Errorf("unknown org role %q, expected member or admin", t.OrgRole)
+	}
+	return nil
+}
+
+// String describes the target in output.
+func (t targetConfig) String() string {
+	scope := t.Org
+	if t.Repo != "" {
+		scope += "/" + t.Repo
+	}
+	return fmt.Sprintf("%s %s", t.Provider, scope)
+}
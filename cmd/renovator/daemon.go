@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// timeWindow is a daily window of time given as minutes since midnight. A window ending before it starts wraps
+// around midnight.
+type timeWindow struct {
+	start, end int
+}
+
+// quietHours are the windows during which daemon mode does not run passes.
+type quietHours []timeWindow
+
+// parseQuietHours parses comma separated HH:MM-HH:MM windows, e.g. "18:00-09:00,12:00-13:00".
+func parseQuietHours(value string) (quietHours, error) {
+	var windows quietHours
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		startText, endText, ok := strings.Cut(part, "-")
+		if !ok {
+			return nil, fmt.Errorf("invalid quiet hours %q, expected HH:MM-HH:MM", part)
+		}
+		start, err := time.Parse("15:04", strings.TrimSpace(startText))
+		if err != nil {
+			return nil, fmt.Errorf("invalid quiet hours %q: %w", part, err)
+		}
+		end, err := time.Parse("15:04", strings.TrimSpace(endText))
+		if err != nil {
+			return nil, fmt.Errorf("invalid quiet hours %q: %w", part, err)
+		}
+		windows = append(windows, timeWindow{
+			start: start.Hour()*60 + start.Minute(),
+			end:   end.Hour()*60 + end.Minute(),
+		})
+	}
+	return windows, nil
+}
+
+// contains reports whether t falls into any of the quiet hours.
+func (q quietHours) contains(t time.Time) bool {
+	minute := t.Hour()*60 + t.Minute()
+	for _, w := range q {
+		if w.start <= w.end && minute >= w.start && minute < w.end {
+			return true
+		}
+		if w.start > w.end && (minute >= w.start || minute < w.end) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseSchedule parses a standard five field cron expression or a descriptor such as @hourly or @every 30m.
+func parseSchedule(schedule string) (cron.Schedule, error) {
+	sched, err := cron.ParseStandard(schedule)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schedule %q: %w", schedule, err)
+	}
+	return sched, nil
+}
+
+// runDaemon runs pass at the times of the schedule, skipping times within quiet hours, until ctx is done.
+func runDaemon(ctx context.Context, sched cron.Schedule, quiet quietHours, pass func(context.Context)) error {
+	for {
+		next := sched.Next(time.Now())
+		if next.IsZero() {
+			return fmt.Errorf("schedule never runs")
+		}
+		log.Printf("Next pass at %s", next.Format(time.DateTime))
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(time.Until(next)):
+		}
+		if quiet.contains(time.Now()) {
+			log.Printf("Skipping pass during quiet hours")
+			continue
+		}
+		pass(ctx)
+	}
+}
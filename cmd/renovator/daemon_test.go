@@ -0,0 +1,71 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQuietHoursContains(t *testing.T) {
+	quiet, err := parseQuietHours("18:00-09:00, 12:00-13:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		at   string
+		want bool
+	}{
+		{"08:59", true},
+		{"09:00", false},
+		{"11:59", false},
+		{"12:00", true},
+		{"12:59", true},
+		{"13:00", false},
+		{"17:59", false},
+		{"18:00", true},
+		{"23:30", true},
+		{"00:00", true},
+	}
+	for _, test := range tests {
+		at, err := time.Parse("15:04", test.at)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := quiet.contains(at); got != test.want {
+			t.Errorf("contains(%s) = %v, want %v", test.at, got, test.want)
+		}
+	}
+}
+
+func TestParseQuietHoursInvalid(t *testing.T) {
+	for _, value := range []string{"18:00", "18:00-25:00", "6pm-9am", "18:00-09:00,x"} {
+		if _, err := parseQuietHours(value); err == nil {
+			t.Errorf("parseQuietHours(%q) succeeded, want an error", value)
+		}
+	}
+}
+
+func TestParseSchedule(t *testing.T) {
+	from := time.Date(2026, 10, 16, 10, 20, 0, 0, time.UTC)
+	tests := []struct {
+		schedule string
+		want     time.Time
+	}{
+		{"*/15 * * * *", time.Date(2026, 10, 16, 10, 30, 0, 0, time.UTC)},
+		{"0 9 * * 1-5", time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2026, 10, 16, 11, 0, 0, 0, time.UTC)},
+		{"@every 30m", from.Add(30 * time.Minute)},
+	}
+	for _, test := range tests {
+		sched, err := parseSchedule(test.schedule)
+		if err != nil {
+			t.Errorf("parseSchedule(%q): %v", test.schedule, err)
+			continue
+		}
+		if got := sched.Next(from); !got.Equal(test.want) {
+			t.Errorf("%q after %s is %s, want %s", test.schedule, from, got, test.want)
+		}
+	}
+	if _, err := parseSchedule("every hour"); err == nil {
+		t.Error("parseSchedule(\"every hour\") succeeded, want an error")
+	}
+}
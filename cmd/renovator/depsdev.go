@@ -0,0 +1,184 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// depsDevConfig configures checking the versions of updates on deps.dev.
+type depsDevConfig struct {
+	// BaseURL of the deps.dev API, https://api.deps.dev by default.
+	BaseURL string `yaml:"baseUrl"`
+	// MinScorecard flags updates whose source repository has an OpenSSF Scorecard score below it, e.g. 5.
+	MinScorecard float64 `yaml:"minScorecard"`
+}
+
+// depsDevSystems map Renovate datasources to deps.dev package systems.
+var depsDevSystems = map[string]string{
+	"npm":   "NPM",
+	"go":    "GO",
+	"maven": "MAVEN",
+	"pypi":  "PYPI",
+	"nuget": "NUGET",
+	"crate": "CARGO",
+}
+
+// renovateBadgePattern matches the datasource in the age and confidence badges of Renovate PR bodies such as
+// https://developer.mend.io/api/mc/badges/age/npm/lodash/4.17.21 or
+// https://badges.renovateapi.com/packages/npm/lodash/4.17.21/age-slim.
+var renovateBadgePattern = regexp.MustCompile(`(?:developer\.mend\.io/api/mc/badges/\w+|badges\.renovateapi\.com/packages)/([\w-]+)/`)
+
+// depsDevChecker compares the from and to versions of updates on deps.dev.
+type depsDevChecker struct {
+	api    *restClient
+	config depsDevConfig
+}
+
+func newDepsDevChecker(c *depsDevConfig) *depsDevChecker {
+	if c == nil {
+		return nil
+	}
+	baseURL := c.BaseURL
+	if baseURL == "" {
+		baseURL = "https://api.deps.dev"
+	}
+	return &depsDevChecker{api: newRESTClient(baseURL+"/v3", http.DefaultClient), config: *c}
+}
+
+// depsDevVersion is the part of a deps.dev package version used.
+type depsDevVersion struct {
+	IsDeprecated    bool     `json:"isDeprecated"`
+	Licenses        []string `json:"licenses"`
+	RelatedProjects []struct {
+		ProjectKey struct {
+			ID string `json:"id"`
+		} `json:"projectKey"`
+		RelationType string `json:"relationType"`
+	} `json:"relatedProjects"`
+}
+
+// sourceRepo returns the project the version is built from, e.g. github.com/lodash/lodash, or "" when unknown.
+func (v *depsDevVersion) sourceRepo() string {
+	for _, project := range v.RelatedProjects {
+		if project.RelationType == "SOURCE_REPO" {
+			return project.ProjectKey.ID
+		}
+	}
+	return ""
+}
+
+// depsDevFindings are what deps.dev tells about an update worth a second look.
+type depsDevFindings struct {
+	// licenseChange describes how the license changed, or is "" when it did not.
+	licenseChange string
+	// warnings are maintenance signals, e.g. a deprecated version or a low scorecard.
+	warnings []string
+}
+
+// check compares the versions of the update of pr, returning nil findings when the package or its versions are
+// not known to deps.dev.
+func (d *depsDevChecker) check(ctx context.Context, pr *PullRequest) (*depsDevFindings, error) {
+	u := parseUpdate(pr)
+	match := renovateBadgePattern.FindStringSubmatch(pr.Body)
+	if u.Dependency == "" || u.From == "" || u.To == "" || match == nil {
+		return nil, nil
+	}
+	system, ok := depsDevSystems[match[1]]
+	if !ok {
+		verbosef("Datasource %s is not known to deps.dev\n", match[1])
+		return nil, nil
+	}
+
+	from, err := d.version(ctx, system, u.Dependency, u.From)
+	if err != nil || from == nil {
+		return nil, err
+	}
+	to, err := d.version(ctx, system, u.Dependency, u.To)
+	if err != nil || to == nil {
+		return nil, err
+	}
+
+	findings := &depsDevFindings{}
+	slices.Sort(from.Licenses)
+	slices.Sort(to.Licenses)
+	if !slices.Equal(from.Licenses, to.Licenses) {
+		findings.licenseChange = fmt.Sprintf(msg("license changed from %s to %s"), licenseList(from.Licenses), licenseList(to.Licenses))
+	}
+	if to.IsDeprecated {
+		findings.warnings = append(findings.warnings, fmt.Sprintf(msg("version %s is deprecated"), u.To))
+	}
+	source := to.sourceRepo()
+	if previous := from.sourceRepo(); previous != "" && source != previous {
+		findings.warnings = append(findings.warnings, fmt.Sprintf(msg("source repository changed from %s to %s"), previous, source))
+	}
+	if d.config.MinScorecard > 0 && source != "" {
+		var project struct {
+			Scorecard *struct {
+				OverallScore float64 `json:"overallScore"`
+			} `json:"scorecard"`
+		}
+		if err := d.api.do(ctx, http.MethodGet, "/projects/"+url.PathEscape(source), nil, &project); err != nil {
+			return nil, err
+		}
+		if project.Scorecard != nil && project.Scorecard.OverallScore < d.config.MinScorecard {
+			findings.warnings = append(findings.warnings,
+				fmt.Sprintf(msg("scorecard of %s is %.1f, below %.1f"), source, project.Scorecard.OverallScore, d.config.MinScorecard))
+		}
+	}
+	return findings, nil
+}
+
+// version fetches a version of a package, returning nil when deps.dev does not know it.
+func (d *depsDevChecker) version(ctx context.Context, system, name, version string) (*depsDevVersion, error) {
+	version = strings.TrimLeft(version, "^~=")
+	if system != "GO" {
+		version = strings.TrimPrefix(version, "v")
+	}
+	path := fmt.Sprintf("/systems/%s/packages/%s/versions/%s", system, url.PathEscape(name), url.PathEscape(version))
+	var v depsDevVersion
+	err := d.api.do(ctx, http.MethodGet, path, nil, &v)
+	if apiErr, ok := err.(*apiError); ok && apiErr.StatusCode == http.StatusNotFound {
+		verbosef("%s %s is not known to deps.dev\n", name, version)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+func licenseList(licenses []string) string {
+	if len(licenses) == 0 {
+		return "none"
+	}
+	return strings.Join(licenses, ", ")
+}
+
+// checkDepsDev warns about what deps.dev tells about the update of pr and reports whether its license changed.
+// Failures are logged as the update is then handled as without deps.dev.
+func (r *runner) checkDepsDev(ctx context.Context, pr *PullRequest) (licenseChanged bool) {
+	if r.depsDev == nil {
+		return false
+	}
+	findings, err := r.depsDev.check(ctx, pr)
+	if err != nil {
+		log.Printf("Error checking %s on deps.dev: %v", pr.URL, err)
+		return false
+	}
+	if findings == nil {
+		return false
+	}
+	for _, warning := range findings.warnings {
+		r.printf(paint(decisionSkipped, msg("Warning: %s"))+"\n", warning)
+	}
+	if findings.licenseChange != "" {
+		r.printf(paint(decisionSkipped, msg("PR %s needs confirming as its %s"))+"\n", pr.Title, findings.licenseChange)
+	}
+	return findings.licenseChange != ""
+}
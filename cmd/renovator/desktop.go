@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// desktopNotify shows an OS desktop notification using notify-send on Linux and BSDs, osascript on macOS and
+// PowerShell on Windows.
+func desktopNotify(title, message string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s", appleScriptString(message), appleScriptString(title))
+		cmd = exec.Command("osascript", "-e", script)
+	case "windows":
+		script := `Add-Type -AssemblyName System.Windows.Forms
+$n = New-Object System.Windows.Forms.NotifyIcon
+$n.Icon = [System.Drawing.SystemIcons]::Information
+$n.Visible = $true
+$n.ShowBalloonTip(10000, $env:RENOVATOR_TITLE, $env:RENOVATOR_MESSAGE, 'Info')
+Start-Sleep -Seconds 1`
+		cmd = exec.Command("powershell", "-NoProfile", "-Command", script)
+		cmd.Env = append(cmd.Environ(), "RENOVATOR_TITLE="+title, "RENOVATOR_MESSAGE="+message)
+	default:
+		cmd = exec.Command("notify-send", "--app-name=renovator", title, message)
+	}
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %w %s", cmd.Path, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// appleScriptString quotes s as an AppleScript string literal.
+func appleScriptString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// discordFieldLimit is the longest value Discord accepts for an embed field.
+const discordFieldLimit = 1024
+
+// discordConfig configures notifications to a Discord channel.
+type discordConfig struct {
+	// Webhook is the URL of a channel webhook.
+	Webhook string `yaml:"webhook"`
+}
+
+// discordNotifier posts the results of a run to Discord as an embed.
+type discordNotifier struct {
+	webhook string
+}
+
+func (n *discordNotifier) Notify(ctx context.Context, summary runSummary) error {
+	color := 0x2ea043 // green
+	if len(summary.Failed) > 0 {
+		color = 0xcf222e // red
+	} else if len(summary.Merged) == 0 {
+		color = 0xd29922 // yellow
+	}
+
+	var fields []map[string]any
+	for _, section := range []struct {
+		title   string
+		results []prResult
+	}{
+		{"Merged", summary.Merged},
+		{"Skipped", summary.Skipped},
+		{"Failed", summary.Failed},
+	} {
+		if len(section.results) == 0 {
+			continue
+		}
+		var value strings.Builder
+		for i, result := range section.results {
+			line := fmt.Sprintf("[%s/%s#%d](%s) %s", result.PR.Org, result.PR.Repo, result.PR.Number, result.PR.URL, result.PR.Title)
+			if result.Reason != "" {
+				line += " (" + result.Reason + ")"
+			}
+			more := fmt.Sprintf("… and %d more", len(section.results)-i)
+			if value.Len()+len(line)+len(more)+2 > discordFieldLimit {
+				value.WriteString(more)
+				break
+			}
+			value.WriteString(line + "\n")
+		}
+		fields = append(fields, map[string]any{
+			"name":  fmt.Sprintf("%s (%d)", section.title, len(section.results)),
+			"value": value.String(),
+		})
+	}
+
+	payload := map[string]any{
+		"username": "Renovator",
+		"embeds": []map[string]any{{
+			"title":  fmt.Sprintf("Renovator run finished in %s", summary.Elapsed.Round(time.Millisecond)),
+			"color":  color,
+			"fields": fields,
+		}},
+	}
+	if err := postJSON(ctx, n.webhook, payload); err != nil {
+		return fmt.Errorf("posting to Discord: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+)
+
+// repoPRLister is implemented by providers that can list the open update PRs of a repository, so that PRs can be
+// found by listing the repositories of the org rather than searching, with -discovery list.
+type repoPRLister interface {
+	repoLister
+	// ListUpdatePRs returns the open PRs of the repository matching the author and, when set, user of query.
+	ListUpdatePRs(ctx context.Context, org, repo string, query SearchQuery) ([]*PullRequest, error)
+}
+
+// searchPRs returns the open update PRs matching query by any of the comma separated authors of query, each
+// searched for separately.
+func (r *runner) searchPRs(ctx context.Context, query SearchQuery) ([]*PullRequest, error) {
+	var prs []*PullRequest
+	for _, author := range splitTokens(query.Author) {
+		query.Author = author
+		found, err := r.searchAuthorPRs(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		for _, pr := range found {
+			pr.Author = author
+		}
+		prs = append(prs, found...)
+	}
+	return prs, nil
+}
+
+// authors returns the authors of -a, which can be given several times or separated by commas.
+func (o options) authors() []string {
+	return splitTokens(o.author)
+}
+
+// authorSuffix names the author of pr when PRs of several authors are processed.
+func (r *runner) authorSuffix(pr *PullRequest) string {
+	if len(r.opts.authors()) < 2 {
+		return ""
+	}
+	return " (" + pr.Author + ")"
+}
+
+// searchAuthorPRs returns the open update PRs matching query, searching for them or, with -discovery list, listing
+// the open PRs of each repository of the org. Listing takes more requests, but is not subject to the rate limit of
+// searches and sees PRs the search index has not caught up with yet.
+func (r *runner) searchAuthorPRs(ctx context.Context, query SearchQuery) ([]*PullRequest, error) {
+	if r.opts.discovery != "list" {
+		prs, err := r.provider.SearchUpdatePRs(ctx, query)
+		var incomplete *incompleteSearchError
+		if errors.As(err, &incomplete) {
+			log.Printf("Warning: %v for %s, narrow the search with -r or use -discovery list", err, query.Author)
+			r.incompleteSearches = append(r.incompleteSearches, fmt.Sprintf("%s: %v for %s", r.target, err, query.Author))
+			err = nil
+		}
+		if err != nil || !r.opts.verifySearch {
+			return prs, err
+		}
+		return r.verifyFound(ctx, query, prs)
+	}
+	lister, ok := r.provider.(repoPRLister)
+	if !ok {
+		return nil, fmt.Errorf("listing the PRs of repositories is not supported by the provider")
+	}
+	repos := []string{query.Repo}
+	if query.Repo == "" {
+		var err error
+		if repos, err = lister.ListRepos(ctx, query.Org); err != nil {
+			return nil, fmt.Errorf("error listing repositories: %w", err)
+		}
+	}
+	var prs []*PullRequest
+	for _, repo := range repos {
+		repoPRs, err := lister.ListUpdatePRs(ctx, query.Org, repo, query)
+		if err != nil {
+			return nil, fmt.Errorf("error listing PRs of %s/%s: %w", query.Org, repo, err)
+		}
+		prs = append(prs, repoPRs...)
+	}
+	verbosef("Listed the PRs of %d repositories\n", len(repos))
+	return prs, nil
+}
+
+// verifyFound re-reads the PRs found by a search through the PR API, as search results can lag behind by minutes,
+// dropping the PRs that are no longer open and refreshing the title, description and head of the others. For a
+// repository, its open PRs are also listed directly when the provider can, adding the ones not indexed yet.
+func (r *runner) verifyFound(ctx context.Context, query SearchQuery, prs []*PullRequest) ([]*PullRequest, error) {
+	found := map[string]bool{}
+	current := make([]*PullRequest, 0, len(prs))
+	for _, pr := range prs {
+		found[pr.URL] = true
+		details, err := r.provider.GetPR(ctx, pr.Org, pr.Repo, pr.Number)
+		if err != nil {
+			verbosef("Error verifying %s: %v\n", pr.URL, err)
+			current = append(current, pr)
+			continue
+		}
+		if details.Merged || details.Closed {
+			verbosef("Search listed %s, which is no longer open\n", pr.URL)
+			continue
+		}
+		pr.Title, pr.Body, pr.HeadSHA, pr.HeadRef = details.Title, details.Body, details.HeadSHA, details.HeadRef
+		current = append(current, pr)
+	}
+	if dropped := len(prs) - len(current); dropped > 0 {
+		infof(msg("Dropped %d PRs no longer open")+"\n", dropped)
+	}
+
+	lister, ok := r.provider.(repoPRLister)
+	if query.Repo == "" || !ok {
+		return current, nil
+	}
+	listed, err := lister.ListUpdatePRs(ctx, query.Org, query.Repo, query)
+	if err != nil {
+		return nil, fmt.Errorf("error listing PRs of %s/%s: %w", query.Org, query.Repo, err)
+	}
+	added := 0
+	for _, pr := range listed {
+		if !found[pr.URL] {
+			current = append(current, pr)
+			added++
+		}
+	}
+	if added > 0 {
+		infof(msg("Added %d PRs the search has not indexed yet")+"\n", added)
+	}
+	return current, nil
+}
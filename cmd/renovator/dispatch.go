@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+const (
+	// dispatchRunPolls and dispatchRunInterval bound how long the run of a dispatched workflow is looked for, as
+	// GitHub does not return it when dispatching.
+	dispatchRunPolls    = 5
+	dispatchRunInterval = 2 * time.Second
+)
+
+// dispatchConfig is a GitHub Actions workflow triggered after merging a PR, e.g. to deploy or run integration tests.
+type dispatchConfig struct {
+	// Workflow is the file name of a workflow run with workflow_dispatch, e.g. deploy.yml.
+	Workflow string `yaml:"workflow"`
+	// Ref is the branch or tag the workflow runs on, by default the branch the PR was merged into.
+	Ref string `yaml:"ref"`
+	// Inputs are the inputs of the workflow.
+	Inputs map[string]string `yaml:"inputs"`
+	// Event is the type of a repository_dispatch event sent instead of running Workflow.
+	Event string `yaml:"event"`
+}
+
+func (c *dispatchConfig) validate() error {
+	if (c.Workflow == "") == (c.Event == "") {
+		return fmt.Errorf("afterMerge needs either a workflow or an event")
+	}
+	return nil
+}
+
+// dispatcher is implemented by providers that can trigger workflows.
+type dispatcher interface {
+	// Dispatch triggers the workflow for pr and returns the URL of the run it started, or "" when the run could
+	// not be found yet.
+	Dispatch(ctx context.Context, pr *PullRequest, c dispatchConfig) (runURL string, err error)
+}
+
+// dispatchAfterMerge triggers the workflow the repository of pr configures to run after merging and returns the URL
+// of its run. Failures are logged as the merge itself has succeeded.
+func (r *runner) dispatchAfterMerge(ctx context.Context, pr *PullRequest, repoCfg *repoConfig) string {
+	if repoCfg.AfterMerge == nil {
+		return ""
+	}
+	provider, ok := r.provider.(dispatcher)
+	if !ok {
+		log.Printf("Triggering workflows is not supported by the provider of %s", r.target)
+		return ""
+	}
+	runURL, err := provider.Dispatch(ctx, pr, *repoCfg.AfterMerge)
+	if err != nil {
+		log.Printf(paint(decisionFailed, "Error triggering workflow after merging %s: %v"), pr.URL, err)
+		return ""
+	}
+	if runURL != "" {
+		r.printf(msg("Triggered workflow run: %s")+"\n", runURL)
+	} else {
+		r.printf("%s\n", msg("Triggered workflow, its run is not listed yet"))
+	}
+	return runURL
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var (
+	// doNotMergeTitlePattern matches title prefixes marking a PR not to be merged such as "WIP:", "[DNM]" or "Do not
+	// merge -"
+	doNotMergeTitlePattern = regexp.MustCompile(`(?i)^\s*\[?(?:wip|dnm|do[ -]not[ -]merge|don'?t[ -]merge)\b\]?`)
+	// doNotMergeLabels are the labels marking a PR not to be merged once separators are replaced by spaces, besides
+	// those starting with "do not merge" such as do-not-merge/hold
+	doNotMergeLabels = []string{"dnm", "dont merge", "don't merge", "wip", "work in progress", "blocked", "hold", "on hold"}
+)
+
+// isDoNotMergeLabel reports whether label marks a PR not to be merged, ignoring case and separators.
+func isDoNotMergeLabel(label string) bool {
+	label = strings.Join(strings.FieldsFunc(strings.ToLower(label), func(r rune) bool {
+		return r == ' ' || r == '-' || r == '_' || r == ':' || r == '/'
+	}), " ")
+	return strings.HasPrefix(label, "do not merge") || slices.Contains(doNotMergeLabels, label)
+}
+
+// doNotMergeMarker returns what marks pr not to be merged by a common convention: a label such as do-not-merge or
+// WIP, a title prefix, or the blocked merge state of GitHub when branch protection does not explain it by missing
+// approvals. It returns "" for PRs not marked.
+func (r *runner) doNotMergeMarker(ctx context.Context, pr *PullRequest) string {
+	for _, label := range pr.Labels {
+		if isDoNotMergeLabel(label) {
+			return fmt.Sprintf("label %q", label)
+		}
+	}
+	if prefix := doNotMergeTitlePattern.FindString(pr.Title); prefix != "" {
+		return fmt.Sprintf("title prefix %q", strings.TrimSpace(prefix))
+	}
+	if pr.MergeState == "blocked" && r.blockedBeyondApprovals(ctx, pr) {
+		return "blocked merge state"
+	}
+	return ""
+}
+
+// blockedBeyondApprovals reports whether a blocked PR has the approvals branch protection requires, so that the
+// approval renovator adds will not unblock it. Providers that cannot tell, and protections requiring resolved
+// conversations, are given the benefit of the doubt.
+func (r *runner) blockedBeyondApprovals(ctx context.Context, pr *PullRequest) bool {
+	reviews, ok := r.provider.(reviewStatusProvider)
+	if !ok {
+		return false
+	}
+	status, err := reviews.ReviewStatus(ctx, pr)
+	if err != nil {
+		verbosef("Error reading the branch protection of %s: %v\n", pr.URL, err)
+		return false
+	}
+	protection := status.Protection
+	return protection != nil && !protection.RequiresConversationResolution &&
+		status.Approvals >= protection.RequiredApprovals
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html/template"
+	"mime/multipart"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// emailConfig configures emailing run reports over SMTP. STARTTLS is used when the server offers it.
+type emailConfig struct {
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	Username string `yaml:"username"`
+	// PasswordVariable is the environment variable holding the SMTP password.
+	PasswordVariable string   `yaml:"passwordVariable"`
+	From             string   `yaml:"from"`
+	To               []string `yaml:"to"`
+	// HTML adds an HTML version of the report to the plain text one.
+	HTML bool `yaml:"html"`
+}
+
+// emailNotifier emails the results of a run.
+type emailNotifier struct {
+	config emailConfig
+}
+
+var emailHTMLTemplate = template.Must(template.New("email").Parse(`<html><body>
+<h2>Renovator run finished in {{.Elapsed}}</h2>
+{{range .Sections}}{{if .Results}}<h3>{{.Title}} ({{len .Results}})</h3>
+<ul>{{range .Results}}<li><a href="{{.PR.URL}}">{{.PR.Org}}/{{.PR.Repo}}#{{.PR.Number}}</a> {{.PR.Title}}{{if .Reason}} ({{.Reason}}){{end}}</li>
+{{end}}</ul>
+{{end}}{{end}}</body></html>
+`))
+
+type emailSection struct {
+	Title   string
+	Results []prResult
+}
+
+func (n *emailNotifier) Notify(ctx context.Context, summary runSummary) error {
+	sections := []emailSection{{"Merged", summary.Merged}, {"Skipped", summary.Skipped}, {"Failed", summary.Failed}}
+	elapsed := summary.Elapsed.Round(time.Millisecond)
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "Renovator run finished in %s\n", elapsed)
+	for _, section := range sections {
+		if len(section.Results) == 0 {
+			continue
+		}
+		fmt.Fprintf(&text, "\n%s (%d):\n", section.Title, len(section.Results))
+		for _, result := range section.Results {
+			fmt.Fprintf(&text, "  %s/%s#%d %s", result.PR.Org, result.PR.Repo, result.PR.Number, result.PR.Title)
+			if result.Reason != "" {
+				fmt.Fprintf(&text, " (%s)", result.Reason)
+			}
+			fmt.Fprintf(&text, "\n    %s\n", result.PR.URL)
+		}
+	}
+
+	var message bytes.Buffer
+	fmt.Fprintf(&message, "From: %s\r\n", n.config.From)
+	fmt.Fprintf(&message, "To: %s\r\n", strings.Join(n.config.To, ", "))
+	fmt.Fprintf(&message, "Subject: Renovator: %d merged, %d skipped, %d failed\r\n",
+		len(summary.Merged), len(summary.Skipped), len(summary.Failed))
+	fmt.Fprintf(&message, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	message.WriteString("MIME-Version: 1.0\r\n")
+	if !n.config.HTML {
+		message.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+		message.WriteString(strings.ReplaceAll(text.String(), "\n", "\r\n"))
+	} else {
+		parts := multipart.NewWriter(&message)
+		fmt.Fprintf(&message, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", parts.Boundary())
+		plain, err := parts.CreatePart(textproto.MIMEHeader{"Content-Type": {"text/plain; charset=utf-8"}})
+		if err != nil {
+			return err
+		}
+		_, _ = plain.Write([]byte(strings.ReplaceAll(text.String(), "\n", "\r\n")))
+		html, err := parts.CreatePart(textproto.MIMEHeader{"Content-Type": {"text/html; charset=utf-8"}})
+		if err != nil {
+			return err
+		}
+		err = emailHTMLTemplate.Execute(html, map[string]any{"Elapsed": elapsed, "Sections": sections})
+		if err != nil {
+			return err
+		}
+		if err := parts.Close(); err != nil {
+			return err
+		}
+	}
+
+	port := n.config.Port
+	if port == 0 {
+		port = 587
+	}
+	var auth smtp.Auth
+	if n.config.Username != "" {
+		auth = smtp.PlainAuth("", n.config.Username, os.Getenv(n.config.PasswordVariable), n.config.Host)
+	}
+	addr := net.JoinHostPort(n.config.Host, strconv.Itoa(port))
+	if err := smtp.SendMail(addr, auth, n.config.From, n.config.To, message.Bytes()); err != nil {
+		return fmt.Errorf("emailing report: %w", err)
+	}
+	return nil
+}
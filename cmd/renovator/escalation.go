@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+)
+
+// escalationConfig escalates PRs that have been open for long and still fail their checks to people, as Renovate
+// will not get them merged by itself.
+type escalationConfig struct {
+	// After is the age from which PRs with failing checks are escalated, e.g. 336h.
+	After time.Duration `yaml:"after"`
+	// Label is added to escalated PRs, needs-human by default.
+	Label string `yaml:"label"`
+	// Assignees are assigned to escalated PRs.
+	Assignees []string `yaml:"assignees"`
+	// AssignMaintainers also assigns the direct collaborators with maintain or admin permission on the repository.
+	AssignMaintainers bool `yaml:"assignMaintainers"`
+}
+
+func (c *escalationConfig) validate() error {
+	if c.After <= 0 {
+		return fmt.Errorf("escalation needs a positive after")
+	}
+	return nil
+}
+
+// escalator is implemented by providers that can label PRs and assign them to the maintainers of their repository.
+type escalator interface {
+	AddLabel(ctx context.Context, pr *PullRequest, label string) error
+	Assign(ctx context.Context, pr *PullRequest, assignees []string) error
+	// Maintainers returns the logins of the users who maintain the repository.
+	Maintainers(ctx context.Context, org, repo string) ([]string, error)
+}
+
+// escalate labels and assigns a PR whose checks are classified as code when it is older than configured, once per
+// PR while renovator runs, and lists it as needing a human in the summary.
+func (r *runner) escalate(ctx context.Context, pr *PullRequest, code reasonCode) {
+	c := r.escalation
+	if c == nil || code != reasonChecksFailed || pr.CreatedAt.IsZero() || time.Since(pr.CreatedAt) < c.After ||
+		r.escalated[pr.URL] {
+		return
+	}
+	if r.escalated == nil {
+		r.escalated = map[string]bool{}
+	}
+	r.escalated[pr.URL] = true
+	r.report.addEscalated(pr)
+	r.printf(paint(decisionFailed, msg("PR %s has been failing its checks for %s, escalating"))+"\n", pr.Title,
+		formatAge(time.Since(pr.CreatedAt)))
+
+	provider, ok := r.provider.(escalator)
+	if !ok {
+		log.Printf("Escalating PRs is not supported by the provider of %s", r.target)
+		return
+	}
+	label := c.Label
+	if label == "" {
+		label = "needs-human"
+	}
+	if err := provider.AddLabel(ctx, pr, label); err != nil {
+		log.Printf("Error labeling %s: %v", pr.URL, err)
+	}
+	assignees := slices.Clone(c.Assignees)
+	if c.AssignMaintainers {
+		maintainers, err := provider.Maintainers(ctx, pr.Org, pr.Repo)
+		if err != nil {
+			log.Printf("Error reading the maintainers of %s/%s: %v", pr.Org, pr.Repo, err)
+		}
+		for _, login := range maintainers {
+			if !slices.Contains(assignees, login) {
+				assignees = append(assignees, login)
+			}
+		}
+	}
+	if len(assignees) == 0 {
+		return
+	}
+	if err := provider.Assign(ctx, pr, assignees); err != nil {
+		log.Printf("Error assigning %s: %v", pr.URL, err)
+	} else {
+		verbosef("Assigned %s to %v\n", pr.URL, assignees)
+	}
+}
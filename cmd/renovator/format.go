@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"os"
+	"text/template"
+)
+
+// formatData is what -format templates are executed with for each processed PR.
+type formatData struct {
+	Target   string
+	Org      string
+	Repo     string
+	PR       int
+	Title    string
+	URL      string
+	Decision string
+	Reason   string
+	RunURL   string
+	// Code classifies Reason, e.g. CONFLICTS or CHECKS_PENDING.
+	Code string
+}
+
+// parseFormat parses a -format template such as '{{.Repo}} {{.PR}} {{.Decision}}', trying it out so that
+// unknown fields are reported before anything is processed.
+func parseFormat(format string) (*template.Template, error) {
+	tmpl, err := template.New("format").Parse(format)
+	if err != nil {
+		return nil, err
+	}
+	if err := tmpl.Execute(io.Discard, formatData{}); err != nil {
+		return nil, err
+	}
+	return tmpl, nil
+}
+
+// printFormatted writes the line for result rendered with tmpl to stdout.
+func printFormatted(tmpl *template.Template, result prResult) {
+	var line bytes.Buffer
+	err := tmpl.Execute(&line, formatData{
+		Target:   result.Target,
+		Org:      result.PR.Org,
+		Repo:     result.PR.Repo,
+		PR:       result.PR.Number,
+		Title:    result.PR.Title,
+		URL:      result.PR.URL,
+		Decision: string(result.Decision),
+		Reason:   result.Reason,
+		RunURL:   result.RunURL,
+		Code:     string(result.Code),
+	})
+	if err != nil {
+		log.Printf("Error formatting output: %v", err)
+		return
+	}
+	if line.Len() == 0 || line.Bytes()[line.Len()-1] != '\n' {
+		line.WriteByte('\n')
+	}
+	_, _ = os.Stdout.Write(line.Bytes())
+}
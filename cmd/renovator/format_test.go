@@ -0,0 +1,41 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		format  string
+		wantErr bool
+	}{
+		{"{{.Repo}} {{.PR}} {{.Decision}}", false},
+		{"{{.Org}}/{{.Repo}}#{{.PR}}: {{.Title}} {{.URL}} {{.Reason}}", false},
+		{"{{.Target}}\t{{.Decision}}\n", false},
+		{"plain text", false},
+		{"{{.Repository}}", true},
+		{"{{.Repo}", true},
+		{"{{if .Reason}}", true},
+	}
+	for _, test := range tests {
+		_, err := parseFormat(test.format)
+		if (err != nil) != test.wantErr {
+			t.Errorf("parseFormat(%q) returned %v, want an error %v", test.format, err, test.wantErr)
+		}
+	}
+}
+
+func TestParseFormatRenders(t *testing.T) {
+	tmpl, err := parseFormat("{{.Repo}} {{.PR}} {{.Decision}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var line strings.Builder
+	if err := tmpl.Execute(&line, formatData{Repo: "svc", PR: 7, Decision: "merged"}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := line.String(), "svc 7 merged"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
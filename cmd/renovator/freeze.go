@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// freezePeriod is a deployment freeze during which PRs are evaluated and reported but never merged.
+type freezePeriod struct {
+	// Start and End bound the freeze. An End without a time of day includes that whole day.
+	Start  time.Time `yaml:"start"`
+	End    time.Time `yaml:"end"`
+	Reason string    `yaml:"reason"`
+	// endDateOnly is whether End was given as a date, rather than a time that happens to be midnight.
+	endDateOnly bool
+}
+
+func (p *freezePeriod) UnmarshalYAML(node *yaml.Node) error {
+	type plain freezePeriod
+	if err := node.Decode((*plain)(p)); err != nil {
+		return err
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == "end" {
+			p.endDateOnly = len(node.Content[i+1].Value) == len("2006-01-02")
+		}
+	}
+	return nil
+}
+
+type freezePeriods []freezePeriod
+
+// active returns the freeze period t falls into, if any.
+func (f freezePeriods) active(t time.Time) (freezePeriod, bool) {
+	for _, period := range f {
+		end := period.End
+		if period.endDateOnly {
+			end = end.AddDate(0, 0, 1)
+		}
+		if !t.Before(period.Start) && t.Before(end) {
+			return period, true
+		}
+	}
+	return freezePeriod{}, false
+}
+
+// loadFreezeCalendar reads the events of an iCalendar feed as freeze periods. Recurrence rules are not expanded.
+func loadFreezeCalendar(url string) (freezePeriods, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("reading freeze calendar %s: %s", url, resp.Status)
+	}
+	periods, err := parseICalendar(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("parsing freeze calendar %s: %w", url, err)
+	}
+	return periods, nil
+}
+
+// parseICalendar reads the DTSTART, DTEND and SUMMARY of all VEVENTs of an iCalendar document.
+func parseICalendar(r io.Reader) (freezePeriods, error) {
+	// unfold continuation lines, which start with a space or tab
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	var periods freezePeriods
+	var event *freezePeriod
+	var startDateOnly bool
+	for _, line := range lines {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		name, params, _ := strings.Cut(name, ";")
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			event, startDateOnly = &freezePeriod{}, false
+		case event == nil:
+			continue
+		case name == "DTSTART":
+			start, err := parseICalendarTime(value, params)
+			if err != nil {
+				return nil, err
+			}
+			event.Start, startDateOnly = start, len(value) == len("20060102")
+		case name == "DTEND":
+			end, err := parseICalendarTime(value, params)
+			if err != nil {
+				return nil, err
+			}
+			event.End, event.endDateOnly = end, len(value) == len("20060102")
+			if event.endDateOnly {
+				// the end date of all day events is exclusive
+				event.End = end.AddDate(0, 0, -1)
+			}
+		case name == "SUMMARY":
+			event.Reason = strings.NewReplacer(`\,`, ",", `\;`, ";", `\n`, " ").Replace(value)
+		case name == "END" && value == "VEVENT":
+			if event.End.IsZero() {
+				// events without an end last the day they start on when that is a date
+				event.End, event.endDateOnly = event.Start, startDateOnly
+			}
+			periods = append(periods, *event)
+			event = nil
+		}
+	}
+	return periods, nil
+}
+
+func parseICalendarTime(value, params string) (time.Time, error) {
+	location := time.Local
+	for _, param := range strings.Split(params, ";") {
+		if tzid, ok := strings.CutPrefix(param, "TZID="); ok {
+			if loc, err := time.LoadLocation(tzid); err == nil {
+				location = loc
+			}
+		}
+	}
+	switch {
+	case len(value) == len("20060102"):
+		return time.ParseInLocation("20060102", value, location)
+	case strings.HasSuffix(value, "Z"):
+		return time.Parse("20060102T150405Z", value)
+	default:
+		return time.ParseInLocation("20060102T150405", value, location)
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestFreezePeriodsActive(t *testing.T) {
+	yamlFreezes := func(t *testing.T, doc string) freezePeriods {
+		var periods freezePeriods
+		if err := yaml.Unmarshal([]byte(doc), &periods); err != nil {
+			t.Fatal(err)
+		}
+		return periods
+	}
+	iCalFreezes := func(t *testing.T, event string) freezePeriods {
+		doc := "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nSUMMARY:Release\r\n" + event + "END:VEVENT\r\nEND:VCALENDAR\r\n"
+		periods, err := parseICalendar(strings.NewReader(doc))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return periods
+	}
+	tests := []struct {
+		name    string
+		periods func(t *testing.T) freezePeriods
+		at      string
+		want    bool
+	}{
+		{"yaml date end includes the day", func(t *testing.T) freezePeriods {
+			return yamlFreezes(t, "- {start: 2026-10-18, end: 2026-10-20}")
+		}, "2026-10-20T23:00:00Z", true},
+		{"yaml date end excludes the next day", func(t *testing.T) freezePeriods {
+			return yamlFreezes(t, "- {start: 2026-10-18, end: 2026-10-20}")
+		}, "2026-10-21T00:00:00Z", false},
+		{"yaml timed end at midnight", func(t *testing.T) freezePeriods {
+			return yamlFreezes(t, "- {start: 2026-10-18T00:00:00Z, end: 2026-10-20T00:00:00Z}")
+		}, "2026-10-20T01:00:00Z", false},
+		{"ical timed end at midnight", func(t *testing.T) freezePeriods {
+			return iCalFreezes(t, "DTSTART:20261018T120000Z\r\nDTEND:20261020T000000Z\r\n")
+		}, "2026-10-20T01:00:00Z", false},
+		{"ical timed end before midnight", func(t *testing.T) freezePeriods {
+			return iCalFreezes(t, "DTSTART:20261018T120000Z\r\nDTEND:20261020T000000Z\r\n")
+		}, "2026-10-19T23:00:00Z", true},
+		{"ical date end is exclusive", func(t *testing.T) freezePeriods {
+			return iCalFreezes(t, "DTSTART;VALUE=DATE:20261018\r\nDTEND;VALUE=DATE:20261020\r\n")
+		}, "2026-10-19T23:00:00", true},
+		{"ical date end excludes the end date", func(t *testing.T) freezePeriods {
+			return iCalFreezes(t, "DTSTART;VALUE=DATE:20261018\r\nDTEND;VALUE=DATE:20261020\r\n")
+		}, "2026-10-20T01:00:00", false},
+		{"ical date without end lasts the day", func(t *testing.T) freezePeriods {
+			return iCalFreezes(t, "DTSTART;VALUE=DATE:20261018\r\n")
+		}, "2026-10-18T23:00:00", true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// times without a zone are local, like iCalendar dates
+			at, err := time.ParseInLocation(time.RFC3339, test.at, time.Local)
+			if err != nil {
+				at, err = time.ParseInLocation("2006-01-02T15:04:05", test.at, time.Local)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, got := test.periods(t).active(at); got != test.want {
+				t.Errorf("active(%s) = %v, want %v", test.at, got, test.want)
+			}
+		})
+	}
+}
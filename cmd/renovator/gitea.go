@@ -0,0 +1,351 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// giteaProvider implements Provider for Gitea and Forgejo instances using the /api/v1 REST API.
+//
+// Gitea cannot search for PRs awaiting review by an arbitrary user, so user scoped searches list the PRs
+// whose review is requested from the owner of the token.
+type giteaProvider struct {
+	api *restClient
+}
+
+func newGiteaProvider(baseURL string, httpClient *http.Client) *giteaProvider {
+	return &giteaProvider{api: newRESTClient(strings.TrimSuffix(baseURL, "/")+"/api/v1", httpClient)}
+}
+
+type giteaUser struct {
+	Login string `json:"login"`
+}
+
+type giteaRepository struct {
+	Name  string    `json:"name"`
+	Owner giteaUser `json:"owner"`
+}
+
+type giteaIssue struct {
+	Number     int              `json:"number"`
+	Title      string           `json:"title"`
+	Body       string           `json:"body"`
+	HTMLURL    string           `json:"html_url"`
+	CreatedAt  time.Time        `json:"created_at"`
+	User       giteaUser        `json:"user"`
+	Repository *giteaRepository `json:"repository"`
+}
+
+type giteaPullRequest struct {
+	Number    int       `json:"number"`
+	Title     string    `json:"title"`
+	Body      string    `json:"body"`
+	HTMLURL   string    `json:"html_url"`
+	CreatedAt time.Time `json:"created_at"`
+	User      giteaUser `json:"user"`
+	State     string    `json:"state"`
+	Merged    bool      `json:"merged"`
+	Mergeable bool      `json:"mergeable"`
+	Head      struct {
+		SHA string `json:"sha"`
+		Ref string `json:"ref"`
+	} `json:"head"`
+	Labels []giteaLabel `json:"labels"`
+}
+
+type giteaLabel struct {
+	Name string `json:"name"`
+}
+
+type giteaCombinedStatus struct {
+	Statuses []struct {
+		Context string `json:"context"`
+		Status  string `json:"status"`
+	} `json:"statuses"`
+}
+
+// giteaAuthor converts a GitHub style app author (app/renovate) to a plain Gitea login.
+func giteaAuthor(author string) string {
+	return strings.TrimPrefix(author, "app/")
+}
+
+func (p *giteaProvider) SearchUpdatePRs(ctx context.Context, query SearchQuery) ([]*PullRequest, error) {
+	// Gitea caps pages at 50 items by default, so that larger pages would look like the last one
+	limit := query.PageSize
+	if limit == 0 || limit > 50 {
+		limit = 50
+	}
+	author := giteaAuthor(query.Author)
+
+	var prs []*PullRequest
+	if query.Repo != "" {
+		for page := 1; ; page++ {
+			params := url.Values{}
+			params.Set("state", "open")
+			params.Set("poster", author)
+			params.Set("limit", fmt.Sprint(limit))
+			params.Set("page", fmt.Sprint(page))
+			path := fmt.Sprintf("/repos/%s/%s/pulls?%s", url.PathEscape(query.Org), url.PathEscape(query.Repo), params.Encode())
+			var pulls []giteaPullRequest
+			if err := p.api.do(ctx, http.MethodGet, path, nil, &pulls); err != nil {
+				return nil, err
+			}
+			for _, pull := range pulls {
+				// older Gitea versions ignore poster
+				if pull.User.Login != author {
+					continue
+				}
+				prs = append(prs, &PullRequest{
+					Org:       query.Org,
+					Repo:      query.Repo,
+					Number:    pull.Number,
+					Title:     pull.Title,
+					Body:      pull.Body,
+					URL:       pull.HTMLURL,
+					CreatedAt: pull.CreatedAt,
+					HeadRef:   pull.Head.Ref,
+				})
+			}
+			if len(pulls) < limit {
+				return prs, nil
+			}
+		}
+	}
+
+	params := url.Values{}
+	params.Set("type", "pulls")
+	params.Set("state", "open")
+	params.Set("owner", query.Org)
+	if query.User != "" {
+		params.Set("review_requested", "true")
+	}
+	params.Set("limit", fmt.Sprint(limit))
+	for page := 1; ; page++ {
+		params.Set("page", fmt.Sprint(page))
+		var issues []giteaIssue
+		if err := p.api.do(ctx, http.MethodGet, "/repos/issues/search?"+params.Encode(), nil, &issues); err != nil {
+			return nil, err
+		}
+		for _, issue := range issues {
+			if issue.User.Login != author || issue.Repository == nil {
+				continue
+			}
+			prs = append(prs, &PullRequest{
+				Org:       issue.Repository.Owner.Login,
+				Repo:      issue.Repository.Name,
+				Number:    issue.Number,
+				Title:     issue.Title,
+				Body:      issue.Body,
+				URL:       issue.HTMLURL,
+				CreatedAt: issue.CreatedAt,
+			})
+		}
+		if len(issues) < limit {
+			return prs, nil
+		}
+	}
+}
+
+func giteaLabelNames(labels []giteaLabel) []string {
+	names := make([]string, 0, len(labels))
+	for _, label := range labels {
+		names = append(names, label.Name)
+	}
+	return names
+}
+
+func (p *giteaProvider) GetPR(ctx context.Context, org, repo string, number int) (*PullRequest, error) {
+	var pull giteaPullRequest
+	path := fmt.Sprintf("/repos/%s/%s/pulls/%d", url.PathEscape(org), url.PathEscape(repo), number)
+	if err := p.api.do(ctx, http.MethodGet, path, nil, &pull); err != nil {
+		return nil, err
+	}
+	return &PullRequest{
+		Org:       org,
+		Repo:      repo,
+		Number:    number,
+		Title:     pull.Title,
+		Body:      pull.Body,
+		URL:       pull.HTMLURL,
+		HeadSHA:   pull.Head.SHA,
+		Merged:    pull.Merged,
+		Closed:    pull.State == "closed" && !pull.Merged,
+		Mergeable: pull.Mergeable,
+		CreatedAt: pull.CreatedAt,
+		HeadRef:   pull.Head.Ref,
+		Labels:    giteaLabelNames(pull.Labels),
+	}, nil
+}
+
+func (p *giteaProvider) EvaluateChecks(ctx context.Context, pr *PullRequest) (bool, error) {
+	var status giteaCombinedStatus
+	path := fmt.Sprintf("/repos/%s/%s/commits/%s/status", url.PathEscape(pr.Org), url.PathEscape(pr.Repo), pr.HeadSHA)
+	if err := p.api.do(ctx, http.MethodGet, path, nil, &status); err != nil {
+		return false, err
+	}
+	for _, s := range status.Statuses {
+		if s.Status != "success" && s.Status != "skipped" {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (p *giteaProvider) Approve(ctx context.Context, pr *PullRequest, comment string) error {
+	review := map[string]string{"event": "APPROVED", "body": comment}
+	path := fmt.Sprintf("/repos/%s/%s/pulls/%d/reviews", url.PathEscape(pr.Org), url.PathEscape(pr.Repo), pr.Number)
+	return p.api.do(ctx, http.MethodPost, path, review, nil)
+}
+
+func (p *giteaProvider) Merge(ctx context.Context, pr *PullRequest, method string) error {
+	return p.MergeWithMessage(ctx, pr, method, "", "")
+}
+
+// MergeWithMessage implements commitMessageMerger. Gitea uses its default title and message for those left "".
+func (p *giteaProvider) MergeWithMessage(ctx context.Context, pr *PullRequest, method, title, body string) error {
+	merge := map[string]string{"Do": method}
+	// Gitea refuses to merge when the head moved on from the one checked
+	if pr.HeadSHA != "" {
+		merge["head_commit_id"] = pr.HeadSHA
+	}
+	if title != "" {
+		merge["MergeTitleField"] = title
+	}
+	if body != "" {
+		merge["MergeMessageField"] = body
+	}
+	path := fmt.Sprintf("/repos/%s/%s/pulls/%d/merge", url.PathEscape(pr.Org), url.PathEscape(pr.Repo), pr.Number)
+	return p.api.do(ctx, http.MethodPost, path, merge, nil)
+}
+
+// Unapprove dismisses the latest approving review of the token owner and cancels a scheduled auto merge.
+func (p *giteaProvider) Unapprove(ctx context.Context, pr *PullRequest) error {
+	var self giteaUser
+	if err := p.api.do(ctx, http.MethodGet, "/user", nil, &self); err != nil {
+		return err
+	}
+	prPath := fmt.Sprintf("/repos/%s/%s/pulls/%d", url.PathEscape(pr.Org), url.PathEscape(pr.Repo), pr.Number)
+	var reviews []struct {
+		ID    int64     `json:"id"`
+		State string    `json:"state"`
+		User  giteaUser `json:"user"`
+	}
+	if err := p.api.do(ctx, http.MethodGet, prPath+"/reviews", nil, &reviews); err != nil {
+		return err
+	}
+	for i := len(reviews) - 1; i >= 0; i-- {
+		if reviews[i].State != "APPROVED" || reviews[i].User.Login != self.Login {
+			continue
+		}
+		dismissal := map[string]string{"message": "Approved by mistake"}
+		path := fmt.Sprintf("%s/reviews/%d/dismissals", prPath, reviews[i].ID)
+		if err := p.api.do(ctx, http.MethodPost, path, dismissal, nil); err != nil {
+			return err
+		}
+		break
+	}
+
+	// responds with 404 when no auto merge is scheduled
+	var apiErr *apiError
+	if err := p.api.do(ctx, http.MethodDelete, prPath+"/merge", nil, nil); err != nil &&
+		!(errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound) {
+		return err
+	}
+	return nil
+}
+
+func (p *giteaProvider) Close(ctx context.Context, pr *PullRequest) error {
+	path := fmt.Sprintf("/repos/%s/%s/pulls/%d", url.PathEscape(pr.Org), url.PathEscape(pr.Repo), pr.Number)
+	return p.api.do(ctx, http.MethodPatch, path, map[string]string{"state": "closed"}, nil)
+}
+
+// ListRepos implements repoLister, leaving out archived repositories.
+func (p *giteaProvider) ListRepos(ctx context.Context, org string) ([]string, error) {
+	var names []string
+	for page := 1; ; page++ {
+		var repos []struct {
+			Name     string `json:"name"`
+			Archived bool   `json:"archived"`
+		}
+		path := fmt.Sprintf("/orgs/%s/repos?limit=50&page=%d", url.PathEscape(org), page)
+		if err := p.api.do(ctx, http.MethodGet, path, nil, &repos); err != nil {
+			return nil, err
+		}
+		for _, repo := range repos {
+			if !repo.Archived {
+				names = append(names, repo.Name)
+			}
+		}
+		if len(repos) < 50 {
+			return names, nil
+		}
+	}
+}
+
+// RequestReview implements reviewRequester.
+func (p *giteaProvider) RequestReview(ctx context.Context, pr *PullRequest, reviewers, teams []string) error {
+	request := map[string][]string{"reviewers": reviewers, "team_reviewers": teams}
+	path := fmt.Sprintf("/repos/%s/%s/pulls/%d/requested_reviewers", url.PathEscape(pr.Org), url.PathEscape(pr.Repo), pr.Number)
+	return p.api.do(ctx, http.MethodPost, path, request, nil)
+}
+
+func (p *giteaProvider) Comment(ctx context.Context, pr *PullRequest, body string) error {
+	comment := map[string]string{"body": body}
+	path := fmt.Sprintf("/repos/%s/%s/issues/%d/comments", url.PathEscape(pr.Org), url.PathEscape(pr.Repo), pr.Number)
+	return p.api.do(ctx, http.MethodPost, path, comment, nil)
+}
+
+// RepoState implements repoStateProvider. Gitea repositories cannot be disabled or locked.
+func (p *giteaProvider) RepoState(ctx context.Context, org, repo string) (repoState, error) {
+	var repository struct {
+		Archived    bool   `json:"archived"`
+		Language    string `json:"language"`
+		Private     bool   `json:"private"`
+		Internal    bool   `json:"internal"`
+		Permissions struct {
+			Push bool `json:"push"`
+		} `json:"permissions"`
+	}
+	path := fmt.Sprintf("/repos/%s/%s", url.PathEscape(org), url.PathEscape(repo))
+	if err := p.api.do(ctx, http.MethodGet, path, nil, &repository); err != nil {
+		return repoState{}, err
+	}
+	state := repoState{
+		Archived:   repository.Archived,
+		CanPush:    repository.Permissions.Push,
+		Language:   repository.Language,
+		Visibility: "public",
+	}
+	switch {
+	case repository.Internal:
+		state.Visibility = "internal"
+	case repository.Private:
+		state.Visibility = "private"
+	}
+	return state, nil
+}
+
+func (p *giteaProvider) RepoTopics(ctx context.Context, org, repo string) ([]string, error) {
+	var topics struct {
+		Topics []string `json:"topics"`
+	}
+	path := fmt.Sprintf("/repos/%s/%s/topics", url.PathEscape(org), url.PathEscape(repo))
+	if err := p.api.do(ctx, http.MethodGet, path, nil, &topics); err != nil {
+		return nil, err
+	}
+	return topics.Topics, nil
+}
+
+func (p *giteaProvider) GetFile(ctx context.Context, org, repo, path string) ([]byte, error) {
+	data, err := p.api.send(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/%s/raw/%s", url.PathEscape(org), url.PathEscape(repo), path), nil)
+	var apiErr *apiError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+		return nil, errFileNotFound
+	}
+	return data, err
+}
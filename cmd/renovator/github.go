@@ -0,0 +1,972 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v50/github"
+)
+
+// githubProvider implements Provider on top of the GitHub REST API.
+type githubProvider struct {
+	client *github.Client
+	// teamMembers caches whether the user is a member of a team when listing PRs, keyed by org/team/user.
+	teamMembers map[string]bool
+}
+
+// newGitHubProvider creates a provider for github.com or, when baseURL is set, a GitHub Enterprise Server.
+func newGitHubProvider(baseURL string, httpClient *http.Client) (*githubProvider, error) {
+	if baseURL == "" {
+		return &githubProvider{client: github.NewClient(httpClient)}, nil
+	}
+	client, err := github.NewEnterpriseClient(baseURL, baseURL, httpClient)
+	if err != nil {
+		return nil, err
+	}
+	return &githubProvider{client: client}, nil
+}
+
+func (p *githubProvider) SearchUpdatePRs(ctx context.Context, query SearchQuery) ([]*PullRequest, error) {
+	var scopeFilter string
+	if query.Repo != "" {
+		scopeFilter = fmt.Sprintf("repo:%s/%s", query.Org, query.Repo)
+	} else if query.User == "" {
+		scopeFilter = fmt.Sprintf("org:%s", query.Org)
+	} else {
+		scopeFilter = fmt.Sprintf("org:%s review-requested:%s", query.Org, query.User)
+	}
+	q := fmt.Sprintf("%s author:%s is:open is:pr archived:false", scopeFilter, query.Author)
+
+	searchOpts := &github.SearchOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	if query.PageSize > 0 {
+		searchOpts.PerPage = query.PageSize
+	}
+	var issues []*github.Issue
+	var total int
+	var incomplete bool
+	for {
+		searchResult, resp, err := p.client.Search.Issues(ctx, q, searchOpts)
+		if err != nil {
+			return nil, ssoError(query.Org, err)
+		}
+		if searchOpts.Page == 0 && resp != nil && strings.HasPrefix(resp.Header.Get("X-GitHub-SSO"), "partial-results") {
+			log.Printf("Warning: search results exclude organizations that require the token to be SSO authorized, "+
+				"authorize it at %s", ssoAuthorizeURL(p.client, query.Org))
+		}
+		issues = append(issues, searchResult.Issues...)
+		total = searchResult.GetTotal()
+		incomplete = incomplete || searchResult.GetIncompleteResults()
+		// the search API returns at most 1000 results, after which there is no next page
+		if resp == nil || resp.NextPage == 0 {
+			break
+		}
+		searchOpts.Page = resp.NextPage
+	}
+
+	prs := make([]*PullRequest, 0, len(issues))
+	for _, issue := range issues {
+		prs = append(prs, &PullRequest{
+			Org:       query.Org,
+			Repo:      repoNameFromURL(issue.GetHTMLURL()),
+			Number:    issue.GetNumber(),
+			Title:     issue.GetTitle(),
+			Body:      issue.GetBody(),
+			URL:       issue.GetHTMLURL(),
+			CreatedAt: issue.GetCreatedAt().Time,
+		})
+	}
+	if incomplete || total > len(prs) {
+		return prs, &incompleteSearchError{Found: len(prs), Total: total}
+	}
+	return prs, nil
+}
+
+func githubLabelNames(labels []*github.Label) []string {
+	names := make([]string, 0, len(labels))
+	for _, label := range labels {
+		names = append(names, label.GetName())
+	}
+	return names
+}
+
+func (p *githubProvider) GetPR(ctx context.Context, org, repo string, number int) (*PullRequest, error) {
+	prDetails, _, err := p.client.PullRequests.Get(ctx, org, repo, number)
+	if err != nil {
+		return nil, ssoError(org, err)
+	}
+	if prDetails == nil {
+		return nil, fmt.Errorf("PR details are nil for %s/%s#%d", org, repo, number)
+	}
+	return &PullRequest{
+		Org:        org,
+		Repo:       repo,
+		Number:     number,
+		Title:      prDetails.GetTitle(),
+		Body:       prDetails.GetBody(),
+		URL:        prDetails.GetHTMLURL(),
+		HeadSHA:    prDetails.GetHead().GetSHA(),
+		BaseRef:    prDetails.GetBase().GetRef(),
+		Merged:     prDetails.GetMerged(),
+		Closed:     prDetails.GetState() == "closed" && !prDetails.GetMerged(),
+		Mergeable:  prDetails.GetMergeable(),
+		CreatedAt:  prDetails.GetCreatedAt().Time,
+		AutoMerge:  prDetails.AutoMerge != nil,
+		Draft:      prDetails.GetDraft(),
+		MergeState: prDetails.GetMergeableState(),
+		Archived:   prDetails.GetBase().GetRepo().GetArchived(),
+		HeadRef:    prDetails.GetHead().GetRef(),
+		Labels:     githubLabelNames(prDetails.Labels),
+	}, nil
+}
+
+func (p *githubProvider) EvaluateChecks(ctx context.Context, pr *PullRequest) (bool, error) {
+	checks, _, err := p.client.Checks.ListCheckRunsForRef(ctx, pr.Org, pr.Repo, pr.HeadSHA, nil)
+	if err != nil {
+		return false, ssoError(pr.Org, err)
+	}
+	for _, check := range checks.CheckRuns {
+		if check.GetConclusion() != "success" && check.GetConclusion() != "skipped" {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (p *githubProvider) Approve(ctx context.Context, pr *PullRequest, comment string) error {
+	review := &github.PullRequestReviewRequest{
+		Body:  github.String(comment),
+		Event: github.String("APPROVE"),
+	}
+	_, _, err := p.client.PullRequests.CreateReview(ctx, pr.Org, pr.Repo, pr.Number, review)
+	return ssoError(pr.Org, err)
+}
+
+func (p *githubProvider) Merge(ctx context.Context, pr *PullRequest, method string) error {
+	return p.MergeWithMessage(ctx, pr, method, "", "")
+}
+
+// MergeWithMessage implements commitMessageMerger. GitHub uses its default title and message for those left "".
+func (p *githubProvider) MergeWithMessage(ctx context.Context, pr *PullRequest, method, title, body string) error {
+	// the head the PR was checked and approved at, so that commits pushed since are not merged unseen
+	options := &github.PullRequestOptions{
+		CommitTitle: title,
+		MergeMethod: method,
+		SHA:         pr.HeadSHA,
+	}
+	_, _, err := p.client.PullRequests.Merge(ctx, pr.Org, pr.Repo, pr.Number, body, options)
+	return ssoError(pr.Org, err)
+}
+
+// Unapprove dismisses the latest approving review of the authenticated user and disables auto-merge.
+func (p *githubProvider) Unapprove(ctx context.Context, pr *PullRequest) error {
+	user, _, err := p.client.Users.Get(ctx, "")
+	if err != nil {
+		return err
+	}
+	reviews, _, err := p.client.PullRequests.ListReviews(ctx, pr.Org, pr.Repo, pr.Number, &github.ListOptions{PerPage: 100})
+	if err != nil {
+		return ssoError(pr.Org, err)
+	}
+	for i := len(reviews) - 1; i >= 0; i-- {
+		review := reviews[i]
+		if review.GetState() != "APPROVED" || review.GetUser().GetLogin() != user.GetLogin() {
+			continue
+		}
+		dismissal := &github.PullRequestReviewDismissalRequest{Message: github.String("Approved by mistake")}
+		if _, _, err := p.client.PullRequests.DismissReview(ctx, pr.Org, pr.Repo, pr.Number, review.GetID(), dismissal); err != nil {
+			return ssoError(pr.Org, err)
+		}
+		break
+	}
+	return p.disableAutoMerge(ctx, pr)
+}
+
+// disableAutoMerge disables auto-merge of the PR when it is enabled. The REST API has no endpoint for it, so the
+// GraphQL API is used.
+func (p *githubProvider) disableAutoMerge(ctx context.Context, pr *PullRequest) error {
+	current, _, err := p.client.PullRequests.Get(ctx, pr.Org, pr.Repo, pr.Number)
+	if err != nil {
+		return ssoError(pr.Org, err)
+	}
+	if current.AutoMerge == nil {
+		return nil
+	}
+	mutation := `mutation($id: ID!) { disablePullRequestAutoMerge(input: {pullRequestId: $id}) { clientMutationId } }`
+	if err := p.graphql(ctx, pr.Org, mutation, map[string]any{"id": current.GetNodeID()}, nil); err != nil {
+		return fmt.Errorf("disabling auto-merge: %w", err)
+	}
+	return nil
+}
+
+// graphql runs a GraphQL query or mutation, decoding its data into data when not nil.
+func (p *githubProvider) graphql(ctx context.Context, org, query string, variables map[string]any, data any) error {
+	body := map[string]interface{}{"query": query, "variables": variables}
+	// the GraphQL endpoint is /graphql on github.com and /api/graphql on Enterprise Server, next to /api/v3
+	req, err := p.client.NewRequest(http.MethodPost, "../graphql", body)
+	if err != nil {
+		return err
+	}
+	var result struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if _, err := p.client.Do(ctx, req, &result); err != nil {
+		return ssoError(org, err)
+	}
+	if len(result.Errors) > 0 {
+		return errors.New(result.Errors[0].Message)
+	}
+	if data == nil {
+		return nil
+	}
+	return json.Unmarshal(result.Data, data)
+}
+
+func (p *githubProvider) Close(ctx context.Context, pr *PullRequest) error {
+	_, _, err := p.client.PullRequests.Edit(ctx, pr.Org, pr.Repo, pr.Number, &github.PullRequest{State: github.String("closed")})
+	return ssoError(pr.Org, err)
+}
+
+// ReviewStatus implements reviewStatusProvider. Reading the branch protection requires admin access to the
+// repository, so it is left unknown for other tokens.
+func (p *githubProvider) ReviewStatus(ctx context.Context, pr *PullRequest) (reviewStatus, error) {
+	reviews, _, err := p.client.PullRequests.ListReviews(ctx, pr.Org, pr.Repo, pr.Number, &github.ListOptions{PerPage: 100})
+	if err != nil {
+		return reviewStatus{}, ssoError(pr.Org, err)
+	}
+	latest := map[string]string{}
+	for _, review := range reviews {
+		// comments do not change the verdict of a reviewer
+		if state := review.GetState(); state != "COMMENTED" {
+			latest[review.GetUser().GetLogin()] = state
+		}
+	}
+	var status reviewStatus
+	for _, state := range latest {
+		if state == "APPROVED" {
+			status.Approvals++
+		}
+	}
+
+	current, _, err := p.client.PullRequests.Get(ctx, pr.Org, pr.Repo, pr.Number)
+	if err != nil {
+		return reviewStatus{}, ssoError(pr.Org, err)
+	}
+	protection, _, err := p.client.Repositories.GetBranchProtection(ctx, pr.Org, pr.Repo, current.GetBase().GetRef())
+	switch {
+	case err == nil:
+		status.Protection = &branchProtection{}
+		if required := protection.GetRequiredPullRequestReviews(); required != nil {
+			status.Protection.RequiredApprovals = required.RequiredApprovingReviewCount
+		}
+		if required := protection.GetRequiredStatusChecks(); required != nil {
+			status.Protection.RequiredChecks = required.Contexts
+		}
+		if required := protection.GetRequiredConversationResolution(); required != nil {
+			status.Protection.RequiresConversationResolution = required.Enabled
+		}
+	case errors.Is(err, github.ErrBranchNotProtected):
+		status.Protection = &branchProtection{}
+	}
+	return status, nil
+}
+
+// CheckRuns implements checkRunsProvider with both the check runs and the commit statuses of the head.
+func (p *githubProvider) CheckRuns(ctx context.Context, pr *PullRequest) (map[string]string, error) {
+	states := map[string]string{}
+	checks, _, err := p.client.Checks.ListCheckRunsForRef(ctx, pr.Org, pr.Repo, pr.HeadSHA, &github.ListCheckRunsOptions{
+		ListOptions: github.ListOptions{PerPage: 100},
+	})
+	if err != nil {
+		return nil, ssoError(pr.Org, err)
+	}
+	for _, check := range checks.CheckRuns {
+		state := check.GetConclusion()
+		if check.GetStatus() != "completed" {
+			state = check.GetStatus()
+		}
+		states[check.GetName()] = state
+	}
+	combined, _, err := p.client.Repositories.GetCombinedStatus(ctx, pr.Org, pr.Repo, pr.HeadSHA, nil)
+	if err != nil {
+		return nil, ssoError(pr.Org, err)
+	}
+	for _, status := range combined.Statuses {
+		states[status.GetContext()] = status.GetState()
+	}
+	return states, nil
+}
+
+// UnresolvedThreads implements reviewThreadsProvider, looking at the first 100 threads.
+func (p *githubProvider) UnresolvedThreads(ctx context.Context, pr *PullRequest) ([]reviewThread, error) {
+	query := `query($owner: String!, $repo: String!, $number: Int!) {
+  repository(owner: $owner, name: $repo) {
+    pullRequest(number: $number) {
+      reviewThreads(first: 100) { nodes { id isResolved comments(first: 1) { nodes { author { __typename login } } } } }
+    }
+  }
+}`
+	var data struct {
+		Repository struct {
+			PullRequest struct {
+				ReviewThreads struct {
+					Nodes []struct {
+						ID         string `json:"id"`
+						IsResolved bool   `json:"isResolved"`
+						Comments   struct {
+							Nodes []struct {
+								Author struct {
+									Typename string `json:"__typename"`
+									Login    string `json:"login"`
+								} `json:"author"`
+							} `json:"nodes"`
+						} `json:"comments"`
+					} `json:"nodes"`
+				} `json:"reviewThreads"`
+			} `json:"pullRequest"`
+		} `json:"repository"`
+	}
+	variables := map[string]any{"owner": pr.Org, "repo": pr.Repo, "number": pr.Number}
+	if err := p.graphql(ctx, pr.Org, query, variables, &data); err != nil {
+		return nil, err
+	}
+	var threads []reviewThread
+	for _, node := range data.Repository.PullRequest.ReviewThreads.Nodes {
+		if node.IsResolved {
+			continue
+		}
+		thread := reviewThread{ID: node.ID}
+		if len(node.Comments.Nodes) > 0 {
+			author := node.Comments.Nodes[0].Author
+			thread.Author = author.Login
+			thread.Bot = author.Typename == "Bot" || strings.HasSuffix(author.Login, "[bot]")
+		}
+		threads = append(threads, thread)
+	}
+	return threads, nil
+}
+
+// ResolveThread implements reviewThreadsProvider.
+func (p *githubProvider) ResolveThread(ctx context.Context, pr *PullRequest, id string) error {
+	mutation := `mutation($id: ID!) { resolveReviewThread(input: {threadId: $id}) { thread { id } } }`
+	return p.graphql(ctx, pr.Org, mutation, map[string]any{"id": id}, nil)
+}
+
+// ListRepos implements repoLister, leaving out archived repositories.
+func (p *githubProvider) ListRepos(ctx context.Context, org string) ([]string, error) {
+	var names []string
+	options := &github.RepositoryListByOrgOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		repos, resp, err := p.client.Repositories.ListByOrg(ctx, org, options)
+		if err != nil {
+			return nil, ssoError(org, err)
+		}
+		for _, repo := range repos {
+			if !repo.GetArchived() {
+				names = append(names, repo.GetName())
+			}
+		}
+		if resp.NextPage == 0 {
+			return names, nil
+		}
+		options.Page = resp.NextPage
+	}
+}
+
+// ListUpdatePRs implements repoPRLister. Like review-requested in searches, a user is matched by the reviews requested
+// from them and from the teams they are a member of.
+func (p *githubProvider) ListUpdatePRs(ctx context.Context, org, repo string, query SearchQuery) ([]*PullRequest, error) {
+	author := query.Author
+	if app, ok := strings.CutPrefix(author, "app/"); ok {
+		author = app + "[bot]"
+	}
+	var prs []*PullRequest
+	options := &github.PullRequestListOptions{State: "open", ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		pulls, resp, err := p.client.PullRequests.List(ctx, org, repo, options)
+		if err != nil {
+			return nil, ssoError(org, err)
+		}
+		for _, pull := range pulls {
+			if !strings.EqualFold(pull.GetUser().GetLogin(), author) {
+				continue
+			}
+			if query.Repo == "" && query.User != "" {
+				requested, err := p.reviewRequested(ctx, org, pull, query.User)
+				if err != nil {
+					return nil, err
+				}
+				if !requested {
+					continue
+				}
+			}
+			prs = append(prs, &PullRequest{
+				Org:       org,
+				Repo:      repo,
+				Number:    pull.GetNumber(),
+				Title:     pull.GetTitle(),
+				Body:      pull.GetBody(),
+				URL:       pull.GetHTMLURL(),
+				CreatedAt: pull.GetCreatedAt().Time,
+				HeadRef:   pull.GetHead().GetRef(),
+			})
+		}
+		if resp.NextPage == 0 {
+			return prs, nil
+		}
+		options.Page = resp.NextPage
+	}
+}
+
+// reviewRequested reports whether review of pull is requested from user or one of their teams.
+func (p *githubProvider) reviewRequested(ctx context.Context, org string, pull *github.PullRequest, user string) (bool, error) {
+	for _, reviewer := range pull.RequestedReviewers {
+		if strings.EqualFold(reviewer.GetLogin(), user) {
+			return true, nil
+		}
+	}
+	for _, team := range pull.RequestedTeams {
+		key := org + "/" + team.GetSlug() + "/" + user
+		member, ok := p.teamMembers[key]
+		if !ok {
+			membership, resp, err := p.client.Teams.GetTeamMembershipBySlug(ctx, org, team.GetSlug(), user)
+			if err != nil && (resp == nil || resp.StatusCode != http.StatusNotFound) {
+				return false, ssoError(org, err)
+			}
+			member = err == nil && membership.GetState() == "active"
+			if p.teamMembers == nil {
+				p.teamMembers = map[string]bool{}
+			}
+			p.teamMembers[key] = member
+		}
+		if member {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ChangedFiles implements changedFilesProvider.
+func (p *githubProvider) ChangedFiles(ctx context.Context, pr *PullRequest) ([]changedFile, error) {
+	var changed []changedFile
+	options := &github.ListOptions{PerPage: 100}
+	for {
+		files, resp, err := p.client.PullRequests.ListFiles(ctx, pr.Org, pr.Repo, pr.Number, options)
+		if err != nil {
+			return nil, ssoError(pr.Org, err)
+		}
+		for _, file := range files {
+			changed = append(changed, changedFile{Path: file.GetFilename(), Patch: file.GetPatch()})
+		}
+		if resp.NextPage == 0 {
+			return changed, nil
+		}
+		options.Page = resp.NextPage
+	}
+}
+
+// MergeChecks implements mergeChecker. A merge commit without check runs yet counts as still running.
+func (p *githubProvider) MergeChecks(ctx context.Context, pr *PullRequest) (completed, passed bool, err error) {
+	current, _, err := p.client.PullRequests.Get(ctx, pr.Org, pr.Repo, pr.Number)
+	if err != nil {
+		return false, false, ssoError(pr.Org, err)
+	}
+	sha := current.GetMergeCommitSHA()
+	if sha == "" {
+		return false, false, fmt.Errorf("%s has no merge commit", pr.URL)
+	}
+	checks, _, err := p.client.Checks.ListCheckRunsForRef(ctx, pr.Org, pr.Repo, sha, nil)
+	if err != nil {
+		return false, false, ssoError(pr.Org, err)
+	}
+	if len(checks.CheckRuns) == 0 {
+		return false, false, nil
+	}
+	passed = true
+	for _, check := range checks.CheckRuns {
+		if check.GetStatus() != "completed" {
+			return false, false, nil
+		}
+		switch check.GetConclusion() {
+		case "success", "skipped", "neutral":
+		default:
+			passed = false
+		}
+	}
+	return true, passed, nil
+}
+
+// Revert implements reverter with the revertPullRequest mutation, which opens a PR reverting the merge of pr.
+func (p *githubProvider) Revert(ctx context.Context, pr *PullRequest, title, body string) (*PullRequest, error) {
+	current, _, err := p.client.PullRequests.Get(ctx, pr.Org, pr.Repo, pr.Number)
+	if err != nil {
+		return nil, ssoError(pr.Org, err)
+	}
+	mutation := `mutation($id: ID!, $title: String!, $body: String!) {
+  revertPullRequest(input: {pullRequestId: $id, title: $title, body: $body}) { revertPullRequest { number } }
+}`
+	var data struct {
+		RevertPullRequest struct {
+			RevertPullRequest struct {
+				Number int `json:"number"`
+			} `json:"revertPullRequest"`
+		} `json:"revertPullRequest"`
+	}
+	variables := map[string]any{"id": current.GetNodeID(), "title": title, "body": body}
+	if err := p.graphql(ctx, pr.Org, mutation, variables, &data); err != nil {
+		return nil, err
+	}
+	return p.GetPR(ctx, pr.Org, pr.Repo, data.RevertPullRequest.RevertPullRequest.Number)
+}
+
+// Dispatch implements dispatcher, looking for the run of the workflow for a few seconds after triggering it.
+func (p *githubProvider) Dispatch(ctx context.Context, pr *PullRequest, c dispatchConfig) (string, error) {
+	since := time.Now().UTC().Add(-time.Minute).Format(time.RFC3339)
+	ref := c.Ref
+	if ref == "" {
+		ref = pr.BaseRef
+	}
+	if c.Event != "" {
+		u := parseUpdate(pr)
+		payload, err := json.Marshal(map[string]any{
+			"pr": pr.URL, "number": pr.Number, "dependency": u.Dependency, "from": u.From, "to": u.To,
+		})
+		if err != nil {
+			return "", err
+		}
+		raw := json.RawMessage(payload)
+		if _, _, err := p.client.Repositories.Dispatch(ctx, pr.Org, pr.Repo, github.DispatchRequestOptions{EventType: c.Event, ClientPayload: &raw}); err != nil {
+			return "", ssoError(pr.Org, err)
+		}
+	} else {
+		inputs := map[string]any{}
+		for name, value := range c.Inputs {
+			inputs[name] = value
+		}
+		event := github.CreateWorkflowDispatchEventRequest{Ref: ref, Inputs: inputs}
+		if _, err := p.client.Actions.CreateWorkflowDispatchEventByFileName(ctx, pr.Org, pr.Repo, c.Workflow, event); err != nil {
+			return "", ssoError(pr.Org, err)
+		}
+	}
+
+	options := &github.ListWorkflowRunsOptions{Created: ">=" + since, ListOptions: github.ListOptions{PerPage: 1}}
+	if c.Event != "" {
+		options.Event = "repository_dispatch"
+	} else {
+		options.Event, options.Branch = "workflow_dispatch", ref
+	}
+	for poll := 0; poll < dispatchRunPolls; poll++ {
+		select {
+		case <-time.After(dispatchRunInterval):
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+		var runs *github.WorkflowRuns
+		var err error
+		if c.Event != "" {
+			runs, _, err = p.client.Actions.ListRepositoryWorkflowRuns(ctx, pr.Org, pr.Repo, options)
+		} else {
+			runs, _, err = p.client.Actions.ListWorkflowRunsByFileName(ctx, pr.Org, pr.Repo, c.Workflow, options)
+		}
+		if err != nil {
+			// the workflow has been triggered regardless
+			log.Printf("Error finding the workflow run triggered for %s: %v", pr.URL, ssoError(pr.Org, err))
+			return "", nil
+		}
+		if len(runs.WorkflowRuns) > 0 {
+			return runs.WorkflowRuns[0].GetHTMLURL(), nil
+		}
+	}
+	return "", nil
+}
+
+// RepoState implements repoStateProvider. Unlike the REST API, GraphQL tells whether a repository is locked, e.g.
+// while it is migrated.
+func (p *githubProvider) RepoState(ctx context.Context, org, repo string) (repoState, error) {
+	query := `query($owner: String!, $name: String!) {
+  repository(owner: $owner, name: $name) {
+    isArchived isDisabled isLocked lockReason viewerPermission visibility primaryLanguage { name }
+  }
+}`
+	var data struct {
+		Repository struct {
+			IsArchived       bool   `json:"isArchived"`
+			IsDisabled       bool   `json:"isDisabled"`
+			IsLocked         bool   `json:"isLocked"`
+			LockReason       string `json:"lockReason"`
+			ViewerPermission string `json:"viewerPermission"`
+			Visibility       string `json:"visibility"`
+			PrimaryLanguage  struct {
+				Name string `json:"name"`
+			} `json:"primaryLanguage"`
+		} `json:"repository"`
+	}
+	if err := p.graphql(ctx, org, query, map[string]any{"owner": org, "name": repo}, &data); err != nil {
+		return repoState{}, err
+	}
+	state := repoState{
+		Archived:   data.Repository.IsArchived,
+		Disabled:   data.Repository.IsDisabled,
+		Language:   data.Repository.PrimaryLanguage.Name,
+		Visibility: strings.ToLower(data.Repository.Visibility),
+	}
+	if data.Repository.IsLocked {
+		state.LockReason = data.Repository.LockReason
+		if state.LockReason == "" {
+			state.LockReason = "unknown reason"
+		}
+	}
+	switch data.Repository.ViewerPermission {
+	case "ADMIN", "MAINTAIN", "WRITE", "":
+		// installation tokens have no viewer permission but can only see repositories granted to them
+		state.CanPush = true
+	}
+	return state, nil
+}
+
+// SetMilestone implements planner.
+func (p *githubProvider) SetMilestone(ctx context.Context, pr *PullRequest, title string) (bool, error) {
+	milestones, _, err := p.client.Issues.ListMilestones(ctx, pr.Org, pr.Repo, &github.MilestoneListOptions{
+		State:       "open",
+		ListOptions: github.ListOptions{PerPage: 100},
+	})
+	if err != nil {
+		return false, ssoError(pr.Org, err)
+	}
+	for _, milestone := range milestones {
+		if milestone.GetTitle() != title {
+			continue
+		}
+		request := &github.IssueRequest{Milestone: github.Int(milestone.GetNumber())}
+		if _, _, err := p.client.Issues.Edit(ctx, pr.Org, pr.Repo, pr.Number, request); err != nil {
+			return false, ssoError(pr.Org, err)
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+// AddToProject implements planner. Adding a PR that is already on the board returns its existing item.
+func (p *githubProvider) AddToProject(ctx context.Context, pr *PullRequest, project projectConfig) error {
+	current, _, err := p.client.PullRequests.Get(ctx, pr.Org, pr.Repo, pr.Number)
+	if err != nil {
+		return ssoError(pr.Org, err)
+	}
+	query := `query($owner: String!, $number: Int!, $field: String!) {
+  repositoryOwner(login: $owner) {
+    ... on ProjectV2Owner {
+      projectV2(number: $number) {
+        id
+        field(name: $field) { ... on ProjectV2SingleSelectField { id options { id name } } }
+      }
+    }
+  }
+}`
+	var board struct {
+		RepositoryOwner *struct {
+			ProjectV2 *struct {
+				ID    string `json:"id"`
+				Field *struct {
+					ID      string `json:"id"`
+					Options []struct {
+						ID   string `json:"id"`
+						Name string `json:"name"`
+					} `json:"options"`
+				} `json:"field"`
+			} `json:"projectV2"`
+		} `json:"repositoryOwner"`
+	}
+	variables := map[string]any{"owner": project.Owner, "number": project.Number, "field": project.Field}
+	if err := p.graphql(ctx, pr.Org, query, variables, &board); err != nil {
+		return err
+	}
+	if board.RepositoryOwner == nil || board.RepositoryOwner.ProjectV2 == nil {
+		return fmt.Errorf("project not found")
+	}
+	projectID := board.RepositoryOwner.ProjectV2.ID
+
+	var optionID string
+	if project.Value != "" {
+		field := board.RepositoryOwner.ProjectV2.Field
+		if field == nil {
+			return fmt.Errorf("project has no single select field %s", project.Field)
+		}
+		for _, option := range field.Options {
+			if option.Name == project.Value {
+				optionID = option.ID
+			}
+		}
+		if optionID == "" {
+			return fmt.Errorf("field %s of the project has no option %s", project.Field, project.Value)
+		}
+	}
+
+	mutation := `mutation($project: ID!, $content: ID!) {
+  addProjectV2ItemById(input: {projectId: $project, contentId: $content}) { item { id } }
+}`
+	var added struct {
+		AddProjectV2ItemByID struct {
+			Item struct {
+				ID string `json:"id"`
+			} `json:"item"`
+		} `json:"addProjectV2ItemById"`
+	}
+	variables = map[string]any{"project": projectID, "content": current.GetNodeID()}
+	if err := p.graphql(ctx, pr.Org, mutation, variables, &added); err != nil {
+		return err
+	}
+	if optionID == "" {
+		return nil
+	}
+
+	mutation = `mutation($project: ID!, $item: ID!, $field: ID!, $option: String!) {
+  updateProjectV2ItemFieldValue(input: {projectId: $project, itemId: $item, fieldId: $field, value: {singleSelectOptionId: $option}}) {
+    projectV2Item { id }
+  }
+}`
+	variables = map[string]any{
+		"project": projectID, "item": added.AddProjectV2ItemByID.Item.ID,
+		"field": board.RepositoryOwner.ProjectV2.Field.ID, "option": optionID,
+	}
+	return p.graphql(ctx, pr.Org, mutation, variables, nil)
+}
+
+// RequestReview implements reviewRequester.
+func (p *githubProvider) RequestReview(ctx context.Context, pr *PullRequest, reviewers, teams []string) error {
+	request := github.ReviewersRequest{Reviewers: reviewers, TeamReviewers: teams}
+	_, _, err := p.client.PullRequests.RequestReviewers(ctx, pr.Org, pr.Repo, pr.Number, request)
+	return ssoError(pr.Org, err)
+}
+
+// AddLabel implements escalator.
+func (p *githubProvider) AddLabel(ctx context.Context, pr *PullRequest, label string) error {
+	_, _, err := p.client.Issues.AddLabelsToIssue(ctx, pr.Org, pr.Repo, pr.Number, []string{label})
+	return ssoError(pr.Org, err)
+}
+
+// Assign implements escalator. GitHub ignores assignees without access to the repository.
+func (p *githubProvider) Assign(ctx context.Context, pr *PullRequest, assignees []string) error {
+	_, _, err := p.client.Issues.AddAssignees(ctx, pr.Org, pr.Repo, pr.Number, assignees)
+	return ssoError(pr.Org, err)
+}
+
+// Maintainers implements escalator with the direct collaborators of the repository with maintain or admin
+// permission, leaving out org owners who are admins of every repository.
+func (p *githubProvider) Maintainers(ctx context.Context, org, repo string) ([]string, error) {
+	collaborators, _, err := p.client.Repositories.ListCollaborators(ctx, org, repo, &github.ListCollaboratorsOptions{
+		Affiliation: "direct",
+		ListOptions: github.ListOptions{PerPage: 100},
+	})
+	if err != nil {
+		return nil, ssoError(org, err)
+	}
+	var logins []string
+	for _, user := range collaborators {
+		if user.GetType() == "User" && (user.GetPermissions()["maintain"] || user.GetPermissions()["admin"]) {
+			logins = append(logins, user.GetLogin())
+		}
+	}
+	return logins, nil
+}
+
+func (p *githubProvider) Comment(ctx context.Context, pr *PullRequest, body string) error {
+	comment := &github.IssueComment{Body: github.String(body)}
+	_, _, err := p.client.Issues.CreateComment(ctx, pr.Org, pr.Repo, pr.Number, comment)
+	return ssoError(pr.Org, err)
+}
+
+// RateLimits implements rateLimitProvider.
+func (p *githubProvider) RateLimits(ctx context.Context) ([]rateLimit, error) {
+	limits, _, err := p.client.RateLimits(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var result []rateLimit
+	for _, rate := range []struct {
+		name string
+		rate *github.Rate
+	}{{"core", limits.GetCore()}, {"search", limits.GetSearch()}} {
+		if rate.rate != nil {
+			result = append(result, rateLimit{
+				Name:      rate.name,
+				Limit:     rate.rate.Limit,
+				Remaining: rate.rate.Remaining,
+				Reset:     rate.rate.Reset.Time,
+			})
+		}
+	}
+	return result, nil
+}
+
+// ssoRequiredError is returned when an organization enforces SAML single sign-on and the token has not been
+// authorized for it.
+type ssoRequiredError struct {
+	Org string
+	URL string
+}
+
+func (e *ssoRequiredError) Error() string {
+	return fmt.Sprintf("organization %s enforces SAML SSO and the token is not authorized for it, authorize it at %s",
+		e.Org, e.URL)
+}
+
+// ssoError turns GitHub's "Resource protected by organization SAML enforcement" error into an ssoRequiredError
+// and returns other errors as is.
+func ssoError(org string, err error) error {
+	var errResp *github.ErrorResponse
+	if !errors.As(err, &errResp) || errResp.Response == nil || errResp.Response.StatusCode != http.StatusForbidden ||
+		!strings.Contains(errResp.Message, "SAML enforcement") {
+		return err
+	}
+	url := "https://github.com/settings/tokens"
+	// X-GitHub-SSO: required; url=https://github.com/orgs/<org>/sso?authorization_request=...
+	for _, part := range strings.Split(errResp.Response.Header.Get("X-GitHub-SSO"), ";") {
+		if value, ok := strings.CutPrefix(strings.TrimSpace(part), "url="); ok {
+			url = value
+		}
+	}
+	return &ssoRequiredError{Org: org, URL: url}
+}
+
+// ssoAuthorizeURL is where the token can be SSO authorized for org.
+func ssoAuthorizeURL(client *github.Client, org string) string {
+	web := *client.BaseURL
+	web.Host = strings.TrimPrefix(web.Host, "api.")
+	web.Path = strings.TrimSuffix(strings.TrimSuffix(web.Path, "/"), "/api/v3")
+	return web.String() + "/orgs/" + org + "/sso"
+}
+
+// repoNameFromURL extracts the repository name from a PR HTML URL (https://github.com/<org>/<repo>/pull/<n>).
+func repoNameFromURL(url string) string {
+	parts := strings.Split(url, "/")
+	if len(parts) < 5 {
+		return ""
+	}
+	return parts[4]
+}
+
+// Preflight implements preflightChecker. It reports the authenticated login and, for classic tokens that
+// declare OAuth scopes, fails when a scope needed for the target is missing. Fine-grained and GitHub App tokens
+// do not expose their permissions, so for them only authentication is verified. readOnly runs need no scopes, but
+// only see public repositories without repo.
+func (p *githubProvider) Preflight(ctx context.Context, target targetConfig, readOnly bool) error {
+	user, resp, err := p.client.Users.Get(ctx, "")
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusForbidden {
+			// installation tokens cannot read /user but can list the repositories they were granted
+			if _, _, appErr := p.client.Apps.ListRepos(ctx, &github.ListOptions{PerPage: 1}); appErr == nil {
+				infof("%s\n", msg("Authenticated as a GitHub App installation"))
+				return nil
+			}
+		}
+		return fmt.Errorf("token cannot be used to authenticate: %w", err)
+	}
+	infof(msg("Authenticated as %s")+"\n", user.GetLogin())
+
+	header, ok := resp.Header[http.CanonicalHeaderKey("X-OAuth-Scopes")]
+	if !ok {
+		// fine-grained and App tokens do not declare scopes
+		if readOnly {
+			return nil
+		}
+		return p.verifyApprover(ctx, user, target)
+	}
+	scopes := map[string]bool{}
+	for _, scope := range strings.Split(strings.Join(header, ","), ",") {
+		scopes[strings.TrimSpace(scope)] = true
+	}
+
+	if readOnly {
+		if !scopes["repo"] {
+			log.Printf("Warning: token of %s has no repo scope, private repositories are not included", user.GetLogin())
+		}
+		return nil
+	}
+	var missing []string
+	if !scopes["repo"] {
+		missing = append(missing, "repo (to read, approve and merge pull requests in private repositories)")
+	}
+	if target.Repo == "" && !scopes["read:org"] && !scopes["write:org"] && !scopes["admin:org"] {
+		missing = append(missing, "read:org (to find pull requests with team review requests in the organization)")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("token of %s is missing scopes:\n  %s", user.GetLogin(), strings.Join(missing, "\n  "))
+	}
+	return p.verifyApprover(ctx, user, target)
+}
+
+// verifyApprover checks that user, who approves the PRs of target, is an active member of its org with the role
+// the target requires, so that a run with the wrong token stops before approving anything. Owners that are user
+// accounts are not checked. It warns when user is not the -u whose review requests are processed, or has no
+// two-factor authentication.
+func (p *githubProvider) verifyApprover(ctx context.Context, user *github.User, target targetConfig) error {
+	login := user.GetLogin()
+	if target.User != "" && !strings.EqualFold(login, target.User) {
+		log.Printf("Warning: approving as %s the PRs whose review is requested from %s", login, target.User)
+	}
+	if user.TwoFactorAuthentication != nil && !user.GetTwoFactorAuthentication() {
+		log.Printf("Warning: %s approves without two-factor authentication", login)
+	}
+	if strings.EqualFold(login, target.Org) {
+		return nil
+	}
+	// repositories of user accounts have collaborators instead of members, so only orgs are checked
+	if owner, _, err := p.client.Users.Get(ctx, target.Org); err == nil && owner.GetType() != "Organization" {
+		if target.OrgRole != "" {
+			log.Printf("Warning: %s is a user account, its orgRole %s is not checked", target.Org, target.OrgRole)
+		}
+		verbosef("%s is a user account, not checking the membership of %s\n", target.Org, login)
+		return nil
+	}
+	membership, resp, err := p.client.Organizations.GetOrgMembership(ctx, "", target.Org)
+	switch {
+	case resp != nil && resp.StatusCode == http.StatusNotFound:
+		return fmt.Errorf("%s is not a member of %s", login, target.Org)
+	case err != nil && target.OrgRole == "":
+		// reading memberships needs read:org, which searching a single repository does not
+		verbosef("Cannot verify the membership of %s in %s: %v\n", login, target.Org, err)
+		return nil
+	case err != nil:
+		return fmt.Errorf("verifying the %s role of %s in %s: %w", target.OrgRole, login, target.Org, ssoError(target.Org, err))
+	case membership.GetState() != "active":
+		return fmt.Errorf("membership of %s in %s is %s", login, target.Org, membership.GetState())
+	case target.OrgRole == "admin" && membership.GetRole() != "admin":
+		return fmt.Errorf("%s is a %s of %s, not an admin", login, membership.GetRole(), target.Org)
+	}
+	verbosef("%s is an active %s of %s\n", login, membership.GetRole(), target.Org)
+	return nil
+}
+
+func (p *githubProvider) RepoTopics(ctx context.Context, org, repo string) ([]string, error) {
+	repository, _, err := p.client.Repositories.Get(ctx, org, repo)
+	if err != nil {
+		return nil, ssoError(org, err)
+	}
+	return repository.Topics, nil
+}
+
+func (p *githubProvider) GetFile(ctx context.Context, org, repo, path string) ([]byte, error) {
+	file, _, resp, err := p.client.Repositories.GetContents(ctx, org, repo, path, nil)
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return nil, errFileNotFound
+	}
+	if err != nil {
+		return nil, ssoError(org, err)
+	}
+	if file == nil {
+		return nil, fmt.Errorf("%s is not a file", path)
+	}
+	content, err := file.GetContent()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(content), nil
+}
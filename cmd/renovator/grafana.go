@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// grafanaConfig configures posting annotations of merged updates to Grafana.
+type grafanaConfig struct {
+	URL string `yaml:"url"`
+	// TokenVariable is the environment variable holding a service account token allowed to write annotations.
+	TokenVariable string `yaml:"tokenVariable"`
+	// DashboardUID limits the annotations to a dashboard. By default they are organization wide.
+	DashboardUID string `yaml:"dashboardUid"`
+	// Tags are added to the renovator and dependency tags of the annotations.
+	Tags []string `yaml:"tags"`
+}
+
+// grafanaNotifier posts an annotation per merged dependency spanning the run, listing the repos it was merged in,
+// so that changes on service dashboards can be correlated with dependency updates.
+type grafanaNotifier struct {
+	config grafanaConfig
+}
+
+func (n *grafanaNotifier) Notify(ctx context.Context, summary runSummary) error {
+	if len(summary.Merged) == 0 {
+		return nil
+	}
+	api := newRESTClient(strings.TrimSuffix(n.config.URL, "/"), http.DefaultClient)
+	if token := os.Getenv(n.config.TokenVariable); token != "" {
+		api.header.Set("Authorization", "Bearer "+token)
+	}
+
+	repos := map[string][]string{}
+	for _, result := range summary.Merged {
+		dependency := parseUpdate(result.PR).Dependency
+		if dependency == "" {
+			dependency = result.PR.Title
+		}
+		repos[dependency] = append(repos[dependency], result.PR.Org+"/"+result.PR.Repo)
+	}
+	dependencies := make([]string, 0, len(repos))
+	for dependency := range repos {
+		dependencies = append(dependencies, dependency)
+	}
+	sort.Strings(dependencies)
+
+	end := time.Now()
+	start := end.Add(-summary.Elapsed)
+	for _, dependency := range dependencies {
+		annotation := map[string]any{
+			"time":    start.UnixMilli(),
+			"timeEnd": end.UnixMilli(),
+			"tags":    append([]string{"renovator", dependency}, n.config.Tags...),
+			"text":    fmt.Sprintf("Merged %s update in %s", dependency, strings.Join(repos[dependency], ", ")),
+		}
+		if n.config.DashboardUID != "" {
+			annotation["dashboardUID"] = n.config.DashboardUID
+		}
+		if err := api.do(ctx, http.MethodPost, "/api/annotations", annotation, nil); err != nil {
+			return fmt.Errorf("posting Grafana annotation for %s: %w", dependency, err)
+		}
+	}
+	return nil
+}
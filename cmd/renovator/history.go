@@ -0,0 +1,99 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// runHistoryCommand implements "renovator history", listing the past runs and what they did to PRs according to
+// the state file.
+func runHistoryCommand(args []string) error {
+	flags := flag.NewFlagSet("history", flag.ContinueOnError)
+	stateFile := flags.String("state-file", defaultStatePath(), "State file the results of runs are kept in")
+	sinceValue := flags.String("since", "", "Only list runs since this long ago, e.g. 7d or 12h, or a date such as 2006-01-02")
+	repo := flags.String("repo", "", "Only list PRs of this repository, as name or org/name")
+	dependency := flags.String("dependency", "", "Only list PRs updating this dependency")
+	output := flags.String("output", "text", "Output format: text or json")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *output != "text" && *output != "json" {
+		return fmt.Errorf("unknown output format %q, expected text or json", *output)
+	}
+	since, err := parseSince(*sinceValue, time.Now())
+	if err != nil {
+		return err
+	}
+	records, err := newStateStore(*stateFile).records()
+	if err != nil {
+		return err
+	}
+
+	var listed []stateRecord
+	for _, record := range records {
+		if record.Time.Before(since) {
+			continue
+		}
+		if *repo != "" && record.Repo != *repo && record.Org+"/"+record.Repo != *repo {
+			continue
+		}
+		if *dependency != "" && record.Update.Dependency != *dependency {
+			continue
+		}
+		listed = append(listed, record)
+	}
+	return writeHistory(os.Stdout, listed, *output)
+}
+
+// writeHistory writes the records grouped by run with the counts of each decision, or as JSON.
+func writeHistory(w io.Writer, records []stateRecord, output string) error {
+	if output == "json" {
+		if records == nil {
+			records = []stateRecord{}
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(records)
+	}
+	if len(records) == 0 {
+		_, err := fmt.Fprintln(w, msg("No runs recorded"))
+		return err
+	}
+
+	for start := 0; start < len(records); {
+		run := records[start].Run
+		end := start
+		counts := map[decision]int{}
+		for end < len(records) && records[end].Run.Equal(run) {
+			counts[records[end].Decision]++
+			end++
+		}
+		started := run
+		if started.IsZero() {
+			started = records[start].Time
+		}
+		fmt.Fprintf(w, msg("Run %s: %d merged, %d skipped, %d failed")+"\n", started.Local().Format("2006-01-02 15:04"),
+			counts[decisionMerged], counts[decisionSkipped], counts[decisionFailed])
+
+		table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		for _, record := range records[start:end] {
+			line := fmt.Sprintf("  %s\t%s/%s#%d\t%s", record.Decision, record.Org, record.Repo, record.Number, record.Title)
+			if record.Reason != "" {
+				line += "\t(" + strings.TrimSpace(record.Reason) + ")"
+			}
+			fmt.Fprintln(table, line)
+		}
+		if err := table.Flush(); err != nil {
+			return err
+		}
+		fmt.Fprintln(w)
+		start = end
+	}
+	return nil
+}
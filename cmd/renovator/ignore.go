@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"log"
+	"strings"
+)
+
+// dependabotIgnoreCommand is the comment telling Dependabot not to propose the version of pr again, nor any
+// version of the same kind of update.
+func dependabotIgnoreCommand(pr *PullRequest) string {
+	if kind := parseUpdate(pr).kind(); kind != "" {
+		return "@dependabot ignore this " + kind + " version"
+	}
+	return "@dependabot close"
+}
+
+// ignoreVersion has the bot that opened pr not propose its version again, commenting comment on the PR unless it is
+// empty. Renovate does not recreate PRs closed without merging, so the PR is closed, while Dependabot is told with a
+// comment command and closes the PR by itself.
+func (r *runner) ignoreVersion(ctx context.Context, pr *PullRequest, comment string) {
+	if !strings.Contains(pr.Author, "dependabot") {
+		r.closePR(ctx, pr, comment)
+		return
+	}
+	for _, body := range []string{comment, dependabotIgnoreCommand(pr)} {
+		if body == "" {
+			continue
+		}
+		if err := r.provider.Comment(ctx, pr, body); err != nil {
+			log.Printf(paint(decisionFailed, "Error commenting on PR: %v"), err)
+			r.record(pr, decisionFailed, err.Error())
+			return
+		}
+	}
+	r.printf(paint(decisionSkipped, msg("Told Dependabot to ignore PR: %s"))+"\n", pr.Title)
+	r.record(pr, decisionSkipped, "version ignored")
+}
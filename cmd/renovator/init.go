@@ -0,0 +1,202 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/zalando/go-keyring"
+	"golang.org/x/term"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultConfigPath is where "renovator init" writes the config unless given a path.
+const defaultConfigPath = "renovator.yaml"
+
+var initProviders = []string{"github", "gitea", "forgejo", "azure-devops", "bitbucket-dc"}
+
+// initConfig is the subset of config written by "renovator init", leaving out everything not asked for.
+type initConfig struct {
+	Targets  []initTarget   `yaml:"targets"`
+	Policies []policyConfig `yaml:"policies,omitempty"`
+}
+
+type initTarget struct {
+	Provider      string `yaml:"provider"`
+	BaseURL       string `yaml:"baseUrl,omitempty"`
+	Org           string `yaml:"org"`
+	User          string `yaml:"user,omitempty"`
+	Repo          string `yaml:"repo,omitempty"`
+	TokenVariable string `yaml:"tokenVariable,omitempty"`
+	TokenKeyring  string `yaml:"tokenKeyring,omitempty"`
+	TokenFile     string `yaml:"tokenFile,omitempty"`
+}
+
+// wizard asks questions on stdin.
+type wizard struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// ask prints question and returns the answer, or def when the answer is empty.
+func (w *wizard) ask(question, def string) (string, error) {
+	if def != "" {
+		fmt.Fprintf(w.out, "%s [%s]: ", question, def)
+	} else {
+		fmt.Fprintf(w.out, "%s: ", question)
+	}
+	line, err := w.in.ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+	if answer := strings.TrimSpace(line); answer != "" {
+		return answer, nil
+	}
+	return def, nil
+}
+
+// askRequired asks until the answer is not empty.
+func (w *wizard) askRequired(question string) (string, error) {
+	for {
+		answer, err := w.ask(question, "")
+		if err != nil || answer != "" {
+			return answer, err
+		}
+	}
+}
+
+// askChoice asks until the answer is one of choices.
+func (w *wizard) askChoice(question string, choices []string, def string) (string, error) {
+	for {
+		answer, err := w.ask(fmt.Sprintf("%s (%s)", question, strings.Join(choices, ", ")), def)
+		if err != nil || slices.Contains(choices, answer) {
+			return answer, err
+		}
+		fmt.Fprintf(w.out, msg("Please answer one of %s")+"\n", strings.Join(choices, ", "))
+	}
+}
+
+func (w *wizard) confirm(question string) (bool, error) {
+	answer, err := w.ask(question+" [y/N]", "")
+	return answer == "y" || answer == "Y", err
+}
+
+// runInitCommand implements "renovator init [path]", writing a config file from the answers to a few questions.
+func runInitCommand(args []string) error {
+	if len(args) > 1 {
+		return fmt.Errorf("usage: renovator init [path]")
+	}
+	path := defaultConfigPath
+	if len(args) == 1 {
+		path = args[0]
+	}
+	w := &wizard{in: bufio.NewReader(os.Stdin), out: os.Stdout}
+
+	if _, err := os.Stat(path); err == nil {
+		overwrite, err := w.confirm(fmt.Sprintf(msg("%s exists, overwrite?"), path))
+		if err != nil || !overwrite {
+			return err
+		}
+	}
+
+	var target initTarget
+	var err error
+	if target.Provider, err = w.askChoice(msg("Provider"), initProviders, "github"); err != nil {
+		return err
+	}
+	if target.Provider == "github" {
+		target.BaseURL, err = w.ask(msg("GitHub Enterprise URL (empty for github.com)"), "")
+	} else {
+		target.BaseURL, err = w.askRequired(msg("Base URL"))
+	}
+	if err != nil {
+		return err
+	}
+	if target.Org, err = w.askRequired(msg("Organization")); err != nil {
+		return err
+	}
+	if target.User, err = w.ask(msg("User whose review requests to process (empty to process a single repo)"), ""); err != nil {
+		return err
+	}
+	if target.User == "" {
+		if target.Repo, err = w.askRequired(msg("Repository")); err != nil {
+			return err
+		}
+	}
+
+	auth, err := w.askChoice(msg("Read the token from"), []string{"variable", "keyring", "file"}, "variable")
+	if err != nil {
+		return err
+	}
+	switch auth {
+	case "variable":
+		def := "GITHUB_TOKEN"
+		if target.Provider != "github" {
+			def = strings.ToUpper(strings.ReplaceAll(target.Provider, "-", "_")) + "_TOKEN"
+		}
+		target.TokenVariable, err = w.ask(msg("Environment variable"), def)
+	case "keyring":
+		if target.TokenKeyring, err = w.ask(msg("Keyring account"), target.Org); err != nil {
+			return err
+		}
+		err = w.storeKeyringToken(target.TokenKeyring)
+	case "file":
+		target.TokenFile, err = w.askRequired(msg("Token file"))
+	}
+	if err != nil {
+		return err
+	}
+
+	cfg := initConfig{Targets: []initTarget{target}}
+	if skip, err := w.confirm(msg("Skip major updates?")); err != nil {
+		return err
+	} else if skip {
+		cfg.Policies = append(cfg.Policies, policyConfig{When: `update.type == "major"`, Action: policySkip})
+	}
+	if merge, err := w.confirm(msg("Merge patch updates without asking?")); err != nil {
+		return err
+	} else if merge {
+		cfg.Policies = append(cfg.Policies, policyConfig{When: `update.type == "patch"`, Action: policyMerge})
+	}
+
+	var data bytes.Buffer
+	encoder := yaml.NewEncoder(&data)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(cfg); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data.Bytes(), 0o644); err != nil {
+		return err
+	}
+	fmt.Printf(msg("Config written to %s, run with: renovator -config %s")+"\n", path, path)
+	return nil
+}
+
+// storeKeyringToken offers to store the token for account in the keyring unless it is there already.
+func (w *wizard) storeKeyringToken(account string) error {
+	if _, err := keyring.Get(keyringService, account); err == nil {
+		return nil
+	}
+	store, err := w.confirm(fmt.Sprintf(msg("No token stored for %s, store one now?"), account))
+	if err != nil || !store {
+		return err
+	}
+	var token string
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		token, err = readSecret(fmt.Sprintf(msg("Token for %s")+": ", account))
+	} else {
+		token, err = w.askRequired(fmt.Sprintf(msg("Token for %s"), account))
+	}
+	if err != nil {
+		return err
+	}
+	if err := keyring.Set(keyringService, account, token); err != nil {
+		return err
+	}
+	fmt.Fprintf(w.out, msg("Token for %s stored in keyring")+"\n", account)
+	return nil
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+)
+
+var (
+	// jiraKeyPattern matches Jira issue keys such as OPS-123, capturing the project.
+	jiraKeyPattern = regexp.MustCompile(`\b([A-Z][A-Z0-9]+)-[0-9]+\b`)
+	// upstreamSectionPattern matches the headings of the sections of Renovate PR descriptions quoting upstream
+	// text, whose keys such as CVE-2024 or HHH-123 do not refer to issues of the org.
+	upstreamSectionPattern = regexp.MustCompile(`(?m)^#{1,4}\s*(?:Release Notes|GitHub Vulnerability Alerts|Vulnerabilities)\b`)
+)
+
+// jiraConfig configures recording merges in Jira.
+type jiraConfig struct {
+	BaseURL string `yaml:"baseUrl"`
+	// User is the account e-mail for Jira Cloud API tokens. Without it the token is used as a Data Center personal
+	// access token.
+	User          string `yaml:"user"`
+	TokenVariable string `yaml:"tokenVariable"`
+	// Projects map an org/repo or an org to the Jira project tracking ticket per dependency are created in for
+	// PRs that do not reference an issue. Only issues of these projects are recognized as referenced.
+	Projects map[string]string `yaml:"projects"`
+	// IssueType of created tracking tickets, Task by default.
+	IssueType string `yaml:"issueType"`
+	// Transition is the name of the transition applied to referenced issues after merging, e.g. Done.
+	Transition string `yaml:"transition"`
+}
+
+// jiraRecorder comments merges on the Jira issues PRs reference, or on a tracking ticket per dependency.
+type jiraRecorder struct {
+	api    *restClient
+	config jiraConfig
+}
+
+func newJiraRecorder(c *jiraConfig) (*jiraRecorder, error) {
+	if c == nil || c.BaseURL == "" {
+		return nil, nil
+	}
+	token := os.Getenv(c.TokenVariable)
+	if token == "" {
+		return nil, fmt.Errorf("jira token variable %q is empty", c.TokenVariable)
+	}
+	api := newRESTClient(strings.TrimSuffix(c.BaseURL, "/")+"/rest/api/2", http.DefaultClient)
+	if c.User != "" {
+		api.header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(c.User+":"+token)))
+	} else {
+		api.header.Set("Authorization", "Bearer "+token)
+	}
+	return &jiraRecorder{api: api, config: *c}, nil
+}
+
+// recordMerge comments the merge of pr on the issues it references, transitioning them when configured, or on the
+// tracking ticket of its dependency in the project mapped to its repo. Failures are logged as the merge itself
+// has succeeded. A nil recorder does nothing.
+func (j *jiraRecorder) recordMerge(ctx context.Context, pr *PullRequest) {
+	if j == nil {
+		return
+	}
+	comment := fmt.Sprintf("Merged %s/%s#%d %s\n%s", pr.Org, pr.Repo, pr.Number, pr.Title, pr.URL)
+
+	keys := j.referencedKeys(pr)
+	referenced := len(keys) > 0
+	if !referenced {
+		key, err := j.trackingTicket(ctx, pr)
+		if err != nil {
+			log.Printf("Error finding Jira tracking ticket: %v", err)
+			return
+		}
+		if key == "" {
+			return
+		}
+		keys = []string{key}
+	}
+
+	for _, key := range keys {
+		if err := j.api.do(ctx, http.MethodPost, "/issue/"+key+"/comment", map[string]string{"body": comment}, nil); err != nil {
+			log.Printf("Error commenting on Jira issue %s: %v", key, err)
+			continue
+		}
+		infof(msg("Recorded merge on Jira issue %s")+"\n", key)
+		// tracking tickets stay open for the later updates of the dependency
+		if referenced && j.config.Transition != "" {
+			if err := j.transition(ctx, key, j.config.Transition); err != nil {
+				log.Printf("Error transitioning Jira issue %s: %v", key, err)
+			}
+		}
+	}
+}
+
+// referencedKeys returns the keys of the issues of the configured projects that the title and description of pr
+// reference, leaving out the release notes and advisories quoted from upstream.
+func (j *jiraRecorder) referencedKeys(pr *PullRequest) []string {
+	body := pr.Body
+	if loc := upstreamSectionPattern.FindStringIndex(body); loc != nil {
+		body = body[:loc[0]]
+	}
+	var keys []string
+	seen := map[string]bool{}
+	for _, match := range jiraKeyPattern.FindAllStringSubmatch(pr.Title+"\n"+body, -1) {
+		key, project := match[0], match[1]
+		if seen[key] || !j.knowsProject(project) {
+			continue
+		}
+		seen[key] = true
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func (j *jiraRecorder) knowsProject(project string) bool {
+	for _, configured := range j.config.Projects {
+		if configured == project {
+			return true
+		}
+	}
+	return false
+}
+
+// trackingTicket finds or creates the ticket tracking updates of the dependency of pr in the project mapped to its
+// repo or org, returning "" when neither is mapped.
+func (j *jiraRecorder) trackingTicket(ctx context.Context, pr *PullRequest) (string, error) {
+	project, ok := j.config.Projects[pr.Org+"/"+pr.Repo]
+	if !ok {
+		project, ok = j.config.Projects[pr.Org]
+	}
+	if !ok {
+		return "", nil
+	}
+	dependency := parseUpdate(pr).Dependency
+	if dependency == "" {
+		dependency = pr.Title
+	}
+	summary := "Renovate: update " + dependency
+
+	var found struct {
+		Issues []struct {
+			Key    string `json:"key"`
+			Fields struct {
+				Summary string `json:"summary"`
+			} `json:"fields"`
+		} `json:"issues"`
+	}
+	jql := fmt.Sprintf(`project = %q AND summary ~ %q AND statusCategory != Done ORDER BY created DESC`, project, summary)
+	search := map[string]any{"jql": jql, "fields": []string{"summary"}, "maxResults": 20}
+	if err := j.api.do(ctx, http.MethodPost, "/search", search, &found); err != nil {
+		return "", err
+	}
+	// summary ~ is a fuzzy text match, so compare the summaries exactly
+	for _, issue := range found.Issues {
+		if issue.Fields.Summary == summary {
+			return issue.Key, nil
+		}
+	}
+
+	issueType := j.config.IssueType
+	if issueType == "" {
+		issueType = "Task"
+	}
+	var created struct {
+		Key string `json:"key"`
+	}
+	fields := map[string]any{"fields": map[string]any{
+		"project":     map[string]string{"key": project},
+		"summary":     summary,
+		"issuetype":   map[string]string{"name": issueType},
+		"description": "Tracks the Renovate PRs updating " + dependency + " merged by renovator.",
+	}}
+	if err := j.api.do(ctx, http.MethodPost, "/issue", fields, &created); err != nil {
+		return "", err
+	}
+	infof(msg("Created Jira tracking ticket %s")+"\n", created.Key)
+	return created.Key, nil
+}
+
+// transition applies the transition with the given name to the issue, if it is available in its workflow.
+func (j *jiraRecorder) transition(ctx context.Context, key, name string) error {
+	var available struct {
+		Transitions []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"transitions"`
+	}
+	if err := j.api.do(ctx, http.MethodGet, "/issue/"+key+"/transitions", nil, &available); err != nil {
+		return err
+	}
+	for _, t := range available.Transitions {
+		if strings.EqualFold(t.Name, name) {
+			return j.api.do(ctx, http.MethodPost, "/issue/"+key+"/transitions",
+				map[string]any{"transition": map[string]string{"id": t.ID}}, nil)
+		}
+	}
+	return fmt.Errorf("transition %q is not available", name)
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/zalando/go-keyring"
+	"golang.org/x/term"
+)
+
+// keyringService is the service name tokens are stored under in the OS keychain.
+const keyringService = "renovator"
+
+// keyringToken reads the token stored for account from the OS keychain.
+func keyringToken(account string) (string, error) {
+	token, err := keyring.Get(keyringService, account)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return "", fmt.Errorf("no token stored in keyring for %q, store one with: renovator keyring set %s", account, account)
+	}
+	if err != nil {
+		return "", fmt.Errorf("reading token from keyring: %w", err)
+	}
+	return token, nil
+}
+
+// runKeyringCommand implements "renovator keyring set|delete <account>".
+func runKeyringCommand(args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: renovator keyring set|delete <account>")
+	}
+	action, account := args[0], args[1]
+	switch action {
+	case "set":
+		token, err := readSecret(fmt.Sprintf(msg("Token for %s")+": ", account))
+		if err != nil {
+			return err
+		}
+		if token == "" {
+			return fmt.Errorf("token must not be empty")
+		}
+		if err := keyring.Set(keyringService, account, token); err != nil {
+			return err
+		}
+		fmt.Printf(msg("Token for %s stored in keyring")+"\n", account)
+	case "delete":
+		if err := keyring.Delete(keyringService, account); err != nil {
+			return err
+		}
+		fmt.Printf(msg("Token for %s deleted from keyring")+"\n", account)
+	default:
+		return fmt.Errorf("unknown keyring action %q", action)
+	}
+	return nil
+}
+
+// readSecret reads a line from stdin without echoing it when stdin is a terminal.
+func readSecret(prompt string) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if term.IsTerminal(fd) {
+		fmt.Print(prompt)
+		secret, err := term.ReadPassword(fd)
+		fmt.Println()
+		return strings.TrimSpace(string(secret)), err
+	}
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+)
+
+// listFilter narrows down the PRs listed for confirming together. Empty facets match all PRs.
+type listFilter struct {
+	// text is matched against the repository and dependency of the PRs, ignoring case.
+	text string
+	// bumps are the update kinds (major, minor or patch) to list.
+	bumps map[string]bool
+	// checks are the check statuses (passing or failing) to list.
+	checks map[string]bool
+	// checkStatus caches the check status of PRs by URL, as it takes fetching PR details and checks.
+	checkStatus map[string]string
+}
+
+func (f *listFilter) active() bool {
+	return f.text != "" || len(f.bumps) > 0 || len(f.checks) > 0
+}
+
+// update applies a filtering answer to the filter, returning false when the answer is not one.
+func (f *listFilter) update(answer string) bool {
+	switch answer = strings.ToLower(answer); {
+	case strings.HasPrefix(answer, "/"):
+		f.text = strings.TrimSpace(answer[1:])
+	case answer == "major" || answer == "minor" || answer == "patch":
+		f.bumps = toggle(f.bumps, answer)
+	case answer == "passing" || answer == "failing":
+		f.checks = toggle(f.checks, answer)
+	case answer == "clear":
+		f.text, f.bumps, f.checks = "", nil, nil
+	default:
+		return false
+	}
+	return true
+}
+
+func toggle(set map[string]bool, key string) map[string]bool {
+	if set[key] {
+		delete(set, key)
+		return set
+	}
+	if set == nil {
+		set = map[string]bool{}
+	}
+	set[key] = true
+	return set
+}
+
+// applyListFilter returns the PRs of prs matching the filter.
+func (r *runner) applyListFilter(ctx context.Context, f *listFilter, prs []*PullRequest) []*PullRequest {
+	var listed []*PullRequest
+	for _, pr := range prs {
+		u := parseUpdate(pr)
+		if f.text != "" && !strings.Contains(strings.ToLower(pr.Org+"/"+pr.Repo), f.text) &&
+			!strings.Contains(strings.ToLower(u.Dependency), f.text) {
+			continue
+		}
+		if len(f.bumps) > 0 && !f.bumps[u.kind()] {
+			continue
+		}
+		if len(f.checks) > 0 && !f.checks[r.checkStatus(ctx, f, pr)] {
+			continue
+		}
+		listed = append(listed, pr)
+	}
+	return listed
+}
+
+// checkStatus returns passing or failing depending on the checks of pr, or "" when they cannot be fetched.
+func (r *runner) checkStatus(ctx context.Context, f *listFilter, pr *PullRequest) string {
+	if status, ok := f.checkStatus[pr.URL]; ok {
+		return status
+	}
+	var status string
+	prDetails, err := r.provider.GetPR(ctx, pr.Org, pr.Repo, pr.Number)
+	if err == nil {
+		var passed bool
+		if passed, err = r.provider.EvaluateChecks(ctx, prDetails); err == nil {
+			status = "failing"
+			if passed {
+				status = "passing"
+			}
+		}
+	}
+	if err != nil {
+		log.Printf("Error fetching checks of %s: %v", pr.URL, err)
+	}
+	if f.checkStatus == nil {
+		f.checkStatus = map[string]string{}
+	}
+	f.checkStatus[pr.URL] = status
+	return status
+}
+
+// String describes the active filters in the list heading.
+func (f *listFilter) String() string {
+	var parts []string
+	if f.text != "" {
+		parts = append(parts, fmt.Sprintf("%q", f.text))
+	}
+	for _, facet := range []map[string]bool{f.bumps, f.checks} {
+		keys := make([]string, 0, len(facet))
+		for key := range facet {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		if len(keys) > 0 {
+			parts = append(parts, strings.Join(keys, " or "))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
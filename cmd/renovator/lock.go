@@ -0,0 +1,63 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// errLocked is returned by tryLock when another process holds the lock.
+var errLocked = errors.New("lock is held by another run")
+
+// runLock is an exclusive lock on a file preventing concurrent runs over the same targets. The operating system
+// releases it when the process exits, so crashed runs do not leave stale locks behind.
+type runLock struct {
+	file *os.File
+}
+
+// defaultLockPath returns a lock file in the temporary directory named after the targets, so that runs over the
+// same org and user or repo share a lock.
+func defaultLockPath(targets []targetConfig) string {
+	keys := make([]string, 0, len(targets))
+	for _, target := range targets {
+		keys = append(keys, fmt.Sprintf("%s|%s|%s|%s|%s", target.Provider, target.BaseURL, target.Org, target.User, target.Repo))
+	}
+	sort.Strings(keys)
+	sum := sha256.Sum256([]byte(strings.Join(keys, "\n")))
+	return filepath.Join(os.TempDir(), "renovator-"+hex.EncodeToString(sum[:8])+".lock")
+}
+
+// acquireRunLock takes the lock at path without waiting, returning errLocked when another run holds it.
+func acquireRunLock(path string) (*runLock, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	if err := tryLock(file); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	// record the holder for whoever finds the lock taken
+	_ = file.Truncate(0)
+	_, _ = fmt.Fprintf(file, "%d\n", os.Getpid())
+	return &runLock{file: file}, nil
+}
+
+// lockHolder returns the process ID written by the run holding the lock at path, if readable.
+func lockHolder(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "unknown"
+	}
+	return strings.TrimSpace(string(data))
+}
+
+func (l *runLock) release() {
+	_ = unlock(l.file)
+	_ = l.file.Close()
+}
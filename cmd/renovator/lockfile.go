@@ -0,0 +1,211 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// lockfileMaxNames is how many package names are listed per kind of change in the lockfile summary.
+const lockfileMaxNames = 8
+
+// changedFile is a file changed by a PR with its unified diff, which is empty when too large to show.
+type changedFile struct {
+	Path  string
+	Patch string
+}
+
+// changedFilesProvider is implemented by providers that can list the files changed by a PR.
+type changedFilesProvider interface {
+	ChangedFiles(ctx context.Context, pr *PullRequest) ([]changedFile, error)
+}
+
+// lockfileParser extracts the package name or version a lockfile line declares.
+type lockfileParser func(line string) (name, version string)
+
+var (
+	packageLockNamePattern = regexp.MustCompile(`^\s*"(?:.*/)?node_modules/((?:@[^/"]+/)?[^/"]+)": \{`)
+	jsonVersionPattern     = regexp.MustCompile(`^\s*"version": "([^"]+)"`)
+	yarnNamePattern        = regexp.MustCompile(`^"?((?:@[^@/"]+/)?[^@"]+)@`)
+	yarnVersionPattern     = regexp.MustCompile(`^\s+version:? "?([^"\s]+)"?`)
+	pnpmPackagePattern     = regexp.MustCompile(`^\s+'?/?((?:@[^@/]+/)?[^@/\s]+)@(\d[^:('\s]*)`)
+	tomlNamePattern        = regexp.MustCompile(`^name = "([^"]+)"`)
+	tomlVersionPattern     = regexp.MustCompile(`^version = "([^"]+)"`)
+)
+
+// lockfileParsers map the names of the lockfiles understood to their parsers.
+var lockfileParsers = map[string]lockfileParser{
+	"package-lock.json": func(line string) (string, string) {
+		if match := packageLockNamePattern.FindStringSubmatch(line); match != nil {
+			return match[1], ""
+		}
+		if match := jsonVersionPattern.FindStringSubmatch(line); match != nil {
+			return "", match[1]
+		}
+		return "", ""
+	},
+	"yarn.lock": func(line string) (string, string) {
+		if !strings.HasPrefix(line, " ") && strings.HasSuffix(line, ":") {
+			if match := yarnNamePattern.FindStringSubmatch(line); match != nil {
+				return match[1], ""
+			}
+		}
+		if match := yarnVersionPattern.FindStringSubmatch(line); match != nil {
+			return "", match[1]
+		}
+		return "", ""
+	},
+	"pnpm-lock.yaml": func(line string) (string, string) {
+		if match := pnpmPackagePattern.FindStringSubmatch(line); match != nil && strings.HasSuffix(line, ":") {
+			return match[1], match[2]
+		}
+		return "", ""
+	},
+	"go.sum": func(line string) (string, string) {
+		fields := strings.Fields(line)
+		if len(fields) != 3 || strings.HasSuffix(fields[1], "/go.mod") {
+			return "", ""
+		}
+		return fields[0], fields[1]
+	},
+	"Cargo.lock":  tomlLockfileParser,
+	"poetry.lock": tomlLockfileParser,
+}
+
+func tomlLockfileParser(line string) (string, string) {
+	if match := tomlNamePattern.FindStringSubmatch(line); match != nil {
+		return match[1], ""
+	}
+	if match := tomlVersionPattern.FindStringSubmatch(line); match != nil {
+		return "", match[1]
+	}
+	return "", ""
+}
+
+// packageChange is a package added, removed or updated in a lockfile. From is empty for added and To for removed
+// packages.
+type packageChange struct {
+	Name, From, To string
+}
+
+// lockfileChanges are the package changes of the lockfiles changed by a PR.
+type lockfileChanges struct {
+	Files                   []string
+	Added, Removed, Updated []packageChange
+	// Truncated are the lockfiles whose diff is too large to summarize.
+	Truncated []string
+}
+
+// summarizeLockfiles returns the package changes of the lockfiles among files.
+func summarizeLockfiles(files []changedFile) lockfileChanges {
+	var changes lockfileChanges
+	removed, added := map[string]string{}, map[string]string{}
+	for _, file := range files {
+		parse, ok := lockfileParsers[path.Base(file.Path)]
+		if !ok {
+			continue
+		}
+		changes.Files = append(changes.Files, file.Path)
+		if file.Patch == "" {
+			changes.Truncated = append(changes.Truncated, file.Path)
+			continue
+		}
+		parsePatch(file.Patch, parse, removed, added)
+	}
+
+	for name, from := range removed {
+		to, ok := added[name]
+		switch {
+		case !ok:
+			changes.Removed = append(changes.Removed, packageChange{Name: name, From: from})
+		case to != from:
+			changes.Updated = append(changes.Updated, packageChange{Name: name, From: from, To: to})
+		}
+	}
+	for name, to := range added {
+		if _, ok := removed[name]; !ok {
+			changes.Added = append(changes.Added, packageChange{Name: name, To: to})
+		}
+	}
+	for _, list := range [][]packageChange{changes.Added, changes.Removed, changes.Updated} {
+		sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	}
+	return changes
+}
+
+// parsePatch records the versions of the packages in the removed and added lines of patch. The package a version
+// belongs to is the one last declared on the same side of the diff, which includes the unchanged lines.
+func parsePatch(patch string, parse lockfileParser, removed, added map[string]string) {
+	var oldName, newName string
+	for _, line := range strings.Split(patch, "\n") {
+		if line == "" || strings.HasPrefix(line, "@@") || strings.HasPrefix(line, `\`) {
+			continue
+		}
+		side, content := line[0], line[1:]
+		name, version := parse(content)
+		if name != "" {
+			if side != '+' {
+				oldName = name
+			}
+			if side != '-' {
+				newName = name
+			}
+		}
+		if version == "" {
+			continue
+		}
+		switch {
+		case side == '-' && oldName != "":
+			removed[oldName] = version
+		case side == '+' && newName != "":
+			added[newName] = version
+		}
+	}
+}
+
+// String lists the number of changes of each kind followed by the first names of each.
+func (c lockfileChanges) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, msg("Lockfile changes in %s: %d updated, %d added, %d removed")+"\n", strings.Join(c.Files, ", "),
+		len(c.Updated), len(c.Added), len(c.Removed))
+	list := func(kind string, changes []packageChange, describe func(packageChange) string) {
+		if len(changes) == 0 {
+			return
+		}
+		names := make([]string, 0, lockfileMaxNames)
+		for _, change := range changes[:min(len(changes), lockfileMaxNames)] {
+			names = append(names, describe(change))
+		}
+		if len(changes) > lockfileMaxNames {
+			names = append(names, fmt.Sprintf("and %d more", len(changes)-lockfileMaxNames))
+		}
+		fmt.Fprintf(&b, "  %s: %s\n", kind, strings.Join(names, ", "))
+	}
+	list("updated", c.Updated, func(p packageChange) string { return p.Name + " " + p.From + " -> " + p.To })
+	list("added", c.Added, func(p packageChange) string { return p.Name + " " + p.To })
+	list("removed", c.Removed, func(p packageChange) string { return p.Name + " " + p.From })
+	if len(c.Truncated) > 0 {
+		fmt.Fprintf(&b, "  "+msg("diff too large to summarize: %s")+"\n", strings.Join(c.Truncated, ", "))
+	}
+	return b.String()
+}
+
+// showLockfileChanges prints what changed in the lockfiles of pr when the provider can list its changed files.
+// Failures are only shown with -v as the summary is a nicety.
+func (r *runner) showLockfileChanges(ctx context.Context, pr *PullRequest) {
+	provider, ok := r.provider.(changedFilesProvider)
+	if !ok {
+		return
+	}
+	files, err := provider.ChangedFiles(ctx, pr)
+	if err != nil {
+		verbosef("Error listing files changed by %s: %v\n", pr.URL, err)
+		return
+	}
+	if changes := summarizeLockfiles(files); len(changes.Files) > 0 {
+		fmt.Print(changes)
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSummarizeLockfiles(t *testing.T) {
+	tests := []struct {
+		name  string
+		files []changedFile
+		want  lockfileChanges
+	}{
+		{"package-lock.json", []changedFile{{Path: "web/package-lock.json", Patch: `@@ -10,7 +10,7 @@
+     "node_modules/lodash": {
+-      "version": "4.17.20",
++      "version": "4.17.21",
+       "resolved": "https://registry.npmjs.org/lodash/-/lodash-4.17.21.tgz",
++    "node_modules/@types/node": {
++      "version": "20.1.0",
++    },`}}, lockfileChanges{
+			Files:   []string{"web/package-lock.json"},
+			Added:   []packageChange{{Name: "@types/node", To: "20.1.0"}},
+			Updated: []packageChange{{Name: "lodash", From: "4.17.20", To: "4.17.21"}},
+		}},
+		{"yarn.lock", []changedFile{{Path: "yarn.lock", Patch: `@@ -1,4 +1,4 @@
+-lodash@^4.17.20:
+-  version "4.17.20"
++lodash@^4.17.21:
++  version "4.17.21"`}}, lockfileChanges{
+			Files:   []string{"yarn.lock"},
+			Updated: []packageChange{{Name: "lodash", From: "4.17.20", To: "4.17.21"}},
+		}},
+		{"go.sum", []changedFile{{Path: "go.sum", Patch: `@@ -1,4 +1,2 @@
+-golang.org/x/text v0.13.0 h1:old=
+-golang.org/x/text v0.13.0/go.mod h1:old=
++golang.org/x/text v0.14.0 h1:new=
++golang.org/x/text v0.14.0/go.mod h1:new=
+-github.com/pkg/errors v0.9.1 h1:gone=`}}, lockfileChanges{
+			Files:   []string{"go.sum"},
+			Removed: []packageChange{{Name: "github.com/pkg/errors", From: "v0.9.1"}},
+			Updated: []packageChange{{Name: "golang.org/x/text", From: "v0.13.0", To: "v0.14.0"}},
+		}},
+		{"Cargo.lock", []changedFile{{Path: "Cargo.lock", Patch: `@@ -5,3 +5,3 @@
+ name = "serde"
+-version = "1.0.188"
++version = "1.0.189"`}}, lockfileChanges{
+			Files:   []string{"Cargo.lock"},
+			Updated: []packageChange{{Name: "serde", From: "1.0.188", To: "1.0.189"}},
+		}},
+		{"truncated and other files", []changedFile{{Path: "pnpm-lock.yaml"}, {Path: "package.json", Patch: "+x"}},
+			lockfileChanges{Files: []string{"pnpm-lock.yaml"}, Truncated: []string{"pnpm-lock.yaml"}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := summarizeLockfiles(test.files); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"embed"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// builtinCatalogs are the message catalogs shipped with renovator, one per language. messages/en.yaml lists every
+// translatable message and is where translations start from.
+//
+//go:embed messages/*.yaml
+var builtinCatalogs embed.FS
+
+// messages translates the English prompts and reports, nil when they are printed in English.
+var messages map[string]string
+
+// msg returns the translation of the English message text, or text itself when the catalog does not translate it.
+func msg(text string) string {
+	if translated := messages[text]; translated != "" {
+		return translated
+	}
+	return text
+}
+
+// formatVerb matches the fmt verbs of a message.
+var formatVerb = regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+
+// loadMessages loads the catalog name, which is either a built-in language such as en or the path of a YAML file
+// mapping English messages to their translations. Translations must keep the verbs of their messages in order.
+func loadMessages(name string) error {
+	english, err := readCatalog(builtinCatalogs.ReadFile, "messages/en.yaml")
+	if err != nil {
+		return err
+	}
+	catalog, err := readCatalog(builtinCatalogs.ReadFile, "messages/"+name+".yaml")
+	if err != nil {
+		if catalog, err = readCatalog(os.ReadFile, name); err != nil {
+			return err
+		}
+	}
+	for text, translated := range catalog {
+		if _, ok := english[text]; !ok {
+			log.Printf("Message catalog %s translates an unknown message: %q", name, text)
+			continue
+		}
+		if translated != "" && !slices.Equal(formatVerb.FindAllString(text, -1), formatVerb.FindAllString(translated, -1)) {
+			return fmt.Errorf("message catalog %s: translation %q does not keep the verbs of %q", name, translated, text)
+		}
+	}
+	messages = catalog
+	return nil
+}
+
+func readCatalog(read func(string) ([]byte, error), path string) (map[string]string, error) {
+	data, err := read(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading message catalog: %w", err)
+	}
+	var catalog map[string]string
+	if err := yaml.Unmarshal(data, &catalog); err != nil {
+		return nil, fmt.Errorf("parsing message catalog %s: %w", path, err)
+	}
+	return catalog, nil
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// metricsGroups are the keys merges can be grouped by in metrics.
+var metricsGroups = map[string]func(stateRecord) string{
+	"repo":       func(r stateRecord) string { return r.Org + "/" + r.Repo },
+	"org":        func(r stateRecord) string { return r.Org },
+	"target":     func(r stateRecord) string { return r.Target },
+	"dependency": func(r stateRecord) string { return r.Update.Dependency },
+	"kind":       func(r stateRecord) string { return r.Update.kind() },
+}
+
+// leadTimes are the merge lead-time metrics of a group of merged PRs.
+type leadTimes struct {
+	Group  string
+	Merged int
+	Median time.Duration
+	P90    time.Duration
+}
+
+// MarshalJSON writes the lead times in seconds.
+func (l leadTimes) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Group         string `json:"group"`
+		Merged        int    `json:"merged"`
+		MedianSeconds int64  `json:"medianSeconds"`
+		P90Seconds    int64  `json:"p90Seconds"`
+	}{l.Group, l.Merged, int64(l.Median.Seconds()), int64(l.P90.Seconds())})
+}
+
+// runMetricsCommand implements "renovator metrics", reporting how long Renovate PRs took from being opened to being
+// merged according to the state file.
+func runMetricsCommand(args []string) error {
+	flags := flag.NewFlagSet("metrics", flag.ContinueOnError)
+	stateFile := flags.String("state-file", defaultStatePath(), "State file the results of runs are kept in")
+	sinceValue := flags.String("since", "", "Only include merges since this long ago, e.g. 90d or 12h, or a date such as 2006-01-02")
+	by := flags.String("by", "repo", "Group merges by repo, org, target, dependency, kind or team")
+	teamsFile := flags.String("teams", "", "YAML file mapping each team to globs of the repos it owns, e.g. 'payments: [acme/pay-*]', for -by team")
+	output := flags.String("output", "text", "Output format: text or json")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	group, ok := metricsGroups[*by]
+	if *by == "team" {
+		teams, err := readTeams(*teamsFile)
+		if err != nil {
+			return err
+		}
+		group, ok = teams.owner, true
+	}
+	if !ok {
+		return fmt.Errorf("unknown grouping %q, expected repo, org, target, dependency, kind or team", *by)
+	}
+	if *output != "text" && *output != "json" {
+		return fmt.Errorf("unknown output format %q, expected text or json", *output)
+	}
+	since, err := parseSince(*sinceValue, time.Now())
+	if err != nil {
+		return err
+	}
+	records, err := newStateStore(*stateFile).records()
+	if err != nil {
+		return err
+	}
+
+	durations := map[string][]time.Duration{}
+	var all []time.Duration
+	for _, record := range records {
+		if record.Decision != decisionMerged || record.CreatedAt.IsZero() || record.Time.Before(since) {
+			continue
+		}
+		leadTime := record.Time.Sub(record.CreatedAt)
+		key := group(record)
+		durations[key] = append(durations[key], leadTime)
+		all = append(all, leadTime)
+	}
+	metrics := make([]leadTimes, 0, len(durations))
+	for key, values := range durations {
+		metrics = append(metrics, newLeadTimes(key, values))
+	}
+	sort.Slice(metrics, func(i, j int) bool { return metrics[i].Group < metrics[j].Group })
+	return writeMetrics(os.Stdout, newLeadTimes("all", all), metrics, *output)
+}
+
+// teamRepos maps teams to globs of the repos they own such as acme/payments-*, matched against org/repo.
+type teamRepos map[string][]string
+
+// readTeams reads the teams of -by team from file.
+func readTeams(file string) (teamRepos, error) {
+	if file == "" {
+		return nil, fmt.Errorf("grouping by team requires -teams")
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var teams teamRepos
+	if err := yaml.Unmarshal(data, &teams); err != nil {
+		return nil, fmt.Errorf("parsing teams %s: %w", file, err)
+	}
+	for team, globs := range teams {
+		for _, glob := range globs {
+			if _, err := path.Match(glob, ""); err != nil {
+				return nil, fmt.Errorf("invalid repo %q of team %s: %w", glob, team, err)
+			}
+		}
+	}
+	return teams, nil
+}
+
+// owner returns the first team in name order owning the repo of record, or "" when none does.
+func (t teamRepos) owner(record stateRecord) string {
+	repo := record.Org + "/" + record.Repo
+	for _, team := range slices.Sorted(maps.Keys(t)) {
+		for _, glob := range t[team] {
+			if matched, _ := path.Match(glob, repo); matched {
+				return team
+			}
+		}
+	}
+	return ""
+}
+
+func newLeadTimes(group string, durations []time.Duration) leadTimes {
+	slices.Sort(durations)
+	return leadTimes{Group: group, Merged: len(durations), Median: percentile(durations, 50), P90: percentile(durations, 90)}
+}
+
+// percentile returns the nearest-rank percentile p of the sorted durations.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (p*len(sorted) + 99) / 100
+	return sorted[max(rank, 1)-1]
+}
+
+func writeMetrics(w io.Writer, total leadTimes, metrics []leadTimes, output string) error {
+	if output == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(struct {
+			Total  leadTimes   `json:"total"`
+			Groups []leadTimes `json:"groups"`
+		}{total, metrics})
+	}
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, msg("GROUP\tMERGED\tMEDIAN LEAD TIME\tP90 LEAD TIME"))
+	for _, m := range append(metrics, total) {
+		fmt.Fprintf(table, "%s\t%d\t%s\t%s\n", m.Group, m.Merged, formatAge(m.Median), formatAge(m.P90))
+	}
+	return table.Flush()
+}
+
+// parseSince parses a duration back from now, which also accepts days such as 90d, or a date. An empty value
+// means all time.
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	if date, err := time.ParseInLocation(time.DateOnly, value, time.Local); err == nil {
+		return date, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid since %q, expected e.g. 90d, 12h or 2006-01-02", value)
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+)
+
+// notifier sends the results of a run somewhere people will see them.
+type notifier interface {
+	Notify(ctx context.Context, summary runSummary) error
+}
+
+// notificationsConfig configures the notifiers of the config file.
+type notificationsConfig struct {
+	Teams   *teamsConfig   `yaml:"teams"`
+	Discord *discordConfig `yaml:"discord"`
+	Email   *emailConfig   `yaml:"email"`
+	Grafana *grafanaConfig `yaml:"grafana"`
+	// Webhook is sent events as the run progresses rather than a summary at its end.
+	Webhook *webhookConfig `yaml:"webhook"`
+}
+
+// runSummary is what notifiers are told about a run.
+type runSummary struct {
+	Merged  []prResult
+	Skipped []prResult
+	Failed  []prResult
+	// Broken are the merges whose checks failed with -verify-merge.
+	Broken  []prResult
+	Elapsed time.Duration
+}
+
+func (s runSummary) empty() bool {
+	return len(s.Merged) == 0 && len(s.Skipped) == 0 && len(s.Failed) == 0
+}
+
+// summary returns the results of the run split by decision.
+func (r *report) summary(elapsed time.Duration) runSummary {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := runSummary{Broken: r.broken, Elapsed: elapsed}
+	for _, result := range r.results {
+		switch result.Decision {
+		case decisionMerged:
+			s.Merged = append(s.Merged, result)
+		case decisionSkipped:
+			s.Skipped = append(s.Skipped, result)
+		case decisionFailed:
+			s.Failed = append(s.Failed, result)
+		}
+	}
+	return s
+}
+
+// notifiers creates the notifiers configured.
+func (c notificationsConfig) notifiers() []notifier {
+	var notifiers []notifier
+	if c.Teams != nil && c.Teams.Webhook != "" {
+		notifiers = append(notifiers, &teamsNotifier{webhook: c.Teams.Webhook})
+	}
+	if c.Discord != nil && c.Discord.Webhook != "" {
+		notifiers = append(notifiers, &discordNotifier{webhook: c.Discord.Webhook})
+	}
+	if c.Email != nil && len(c.Email.To) > 0 {
+		notifiers = append(notifiers, &emailNotifier{config: *c.Email})
+	}
+	if c.Grafana != nil && c.Grafana.URL != "" {
+		notifiers = append(notifiers, &grafanaNotifier{config: *c.Grafana})
+	}
+	return notifiers
+}
+
+// notifyAll sends summary to all notifiers, logging failures. Runs without any PRs are not notified.
+func notifyAll(ctx context.Context, notifiers []notifier, summary runSummary) {
+	if summary.empty() {
+		return
+	}
+	for _, n := range notifiers {
+		if err := n.Notify(ctx, summary); err != nil {
+			log.Printf("Error sending notification: %v", err)
+		}
+	}
+}
+
+// postJSON posts payload as JSON to url, failing on non-2xx responses.
+func postJSON(ctx context.Context, url string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	return postBody(ctx, url, body, nil)
+}
+
+// postBody posts a JSON body to url with additional headers, failing on non-2xx responses.
+func postBody(ctx context.Context, url string, body []byte, header http.Header) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s: %s", resp.Status, bytes.TrimSpace(message))
+	}
+	return nil
+}
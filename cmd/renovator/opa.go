@@ -0,0 +1,211 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const defaultOPAQuery = "data.renovator.decision"
+
+// opaConfig configures a Rego policy deciding what renovator may merge. The policy is queried from an OPA server at
+// URL, or evaluated from a local Bundle (a directory or bundle archive) with the opa CLI.
+//
+// The query is given an opaInput and must result in merge, prompt or skip, or an object with an action and a
+// reason. An undefined result leaves the decision to the CEL policies.
+type opaConfig struct {
+	URL    string `yaml:"url"`
+	Bundle string `yaml:"bundle"`
+	// Query is the rule to evaluate, data.renovator.decision by default.
+	Query string `yaml:"query"`
+}
+
+// opaInput is the context a Rego policy decides on.
+type opaInput struct {
+	Target string    `json:"target"`
+	PR     opaPR     `json:"pr"`
+	Repo   opaRepo   `json:"repo"`
+	Update opaUpdate `json:"update"`
+	// Checks are the conclusions, or the statuses while running, of the checks of the head by name, empty when the
+	// provider cannot list them.
+	Checks map[string]string `json:"checks"`
+	Review opaReview         `json:"review"`
+}
+
+type opaPR struct {
+	pluginPR
+	CreatedAt  string   `json:"createdAt"`
+	Labels     []string `json:"labels"`
+	BaseRef    string   `json:"baseRef,omitempty"`
+	HeadRef    string   `json:"headRef,omitempty"`
+	Mergeable  bool     `json:"mergeable"`
+	MergeState string   `json:"mergeState,omitempty"`
+	AutoMerge  bool     `json:"autoMerge"`
+}
+
+// opaReview is the review status of the PR. Protection is null when the protection of the base branch cannot be
+// read.
+type opaReview struct {
+	Approvals  int               `json:"approvals"`
+	Protection *branchProtection `json:"protection"`
+}
+
+type opaRepo struct {
+	Org    string   `json:"org"`
+	Name   string   `json:"name"`
+	Topics []string `json:"topics"`
+}
+
+type opaUpdate struct {
+	update
+	Type string `json:"type"`
+}
+
+// opaInput returns the input of the OPA policy for pr, reading the checks and review status the provider can tell.
+func (r *runner) opaInput(ctx context.Context, pr *PullRequest, u update, topics []string) opaInput {
+	input := opaInput{
+		Target: r.target,
+		PR: opaPR{
+			pluginPR: pluginPR{
+				Org:     pr.Org,
+				Repo:    pr.Repo,
+				Number:  pr.Number,
+				Title:   pr.Title,
+				Body:    pr.Body,
+				URL:     pr.URL,
+				HeadSHA: pr.HeadSHA,
+				Author:  pr.Author,
+			},
+			CreatedAt:  pr.CreatedAt.Format(time.RFC3339),
+			Labels:     pr.Labels,
+			BaseRef:    pr.BaseRef,
+			HeadRef:    pr.HeadRef,
+			Mergeable:  pr.Mergeable,
+			MergeState: pr.MergeState,
+			AutoMerge:  pr.AutoMerge,
+		},
+		Repo:   opaRepo{Org: pr.Org, Name: pr.Repo, Topics: topics},
+		Update: opaUpdate{update: u, Type: u.kind()},
+		Checks: map[string]string{},
+	}
+	if input.PR.Labels == nil {
+		input.PR.Labels = []string{}
+	}
+	if provider, ok := r.provider.(checkRunsProvider); ok {
+		if checks, err := provider.CheckRuns(ctx, pr); err != nil {
+			verbosef("Error reading the checks of %s: %v\n", pr.URL, err)
+		} else {
+			input.Checks = checks
+		}
+	}
+	if provider, ok := r.provider.(reviewStatusProvider); ok {
+		if status, err := provider.ReviewStatus(ctx, pr); err != nil {
+			verbosef("Error reading the review status of %s: %v\n", pr.URL, err)
+		} else {
+			input.Review = opaReview{Approvals: status.Approvals, Protection: status.Protection}
+		}
+	}
+	return input
+}
+
+// opaDecision is the result of a policy query.
+type opaDecision struct {
+	Action string `json:"action"`
+	Reason string `json:"reason"`
+}
+
+func (d *opaDecision) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &d.Action); err == nil {
+		return nil
+	}
+	type decision opaDecision
+	return json.Unmarshal(data, (*decision)(d))
+}
+
+// opaPolicy evaluates a Rego policy.
+type opaPolicy struct {
+	config opaConfig
+	api    *restClient
+}
+
+func newOPAPolicy(c *opaConfig) (*opaPolicy, error) {
+	if c == nil || (c.URL == "" && c.Bundle == "") {
+		return nil, nil
+	}
+	if c.URL != "" && c.Bundle != "" {
+		return nil, fmt.Errorf("either url or bundle can be set for OPA, not both")
+	}
+	p := &opaPolicy{config: *c}
+	if p.config.Query == "" {
+		p.config.Query = defaultOPAQuery
+	}
+	if !strings.HasPrefix(p.config.Query, "data.") {
+		return nil, fmt.Errorf("OPA query %q must start with data.", p.config.Query)
+	}
+	if c.URL != "" {
+		p.api = newRESTClient(strings.TrimSuffix(c.URL, "/"), http.DefaultClient)
+	} else if _, err := exec.LookPath("opa"); err != nil {
+		return nil, fmt.Errorf("evaluating OPA bundle %s requires the opa CLI: %w", c.Bundle, err)
+	}
+	return p, nil
+}
+
+// decide evaluates the policy for input, returning nil when its result is undefined.
+func (p *opaPolicy) decide(ctx context.Context, input opaInput) (*opaDecision, error) {
+	var result json.RawMessage
+	if p.api != nil {
+		var response struct {
+			Result json.RawMessage `json:"result"`
+		}
+		path := "/v1/" + strings.ReplaceAll(p.config.Query, ".", "/")
+		if err := p.api.do(ctx, http.MethodPost, path, map[string]any{"input": input}, &response); err != nil {
+			return nil, err
+		}
+		result = response.Result
+	} else {
+		data, err := json.Marshal(input)
+		if err != nil {
+			return nil, err
+		}
+		var stdout, stderr bytes.Buffer
+		cmd := exec.CommandContext(ctx, "opa", "eval", "--format", "json", "--stdin-input", "--bundle", p.config.Bundle, p.config.Query)
+		cmd.Stdin = bytes.NewReader(data)
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			return nil, fmt.Errorf("opa eval: %w: %s", err, strings.TrimSpace(stderr.String()))
+		}
+		var output struct {
+			Result []struct {
+				Expressions []struct {
+					Value json.RawMessage `json:"value"`
+				} `json:"expressions"`
+			} `json:"result"`
+		}
+		if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+			return nil, fmt.Errorf("parsing opa eval output: %w", err)
+		}
+		if len(output.Result) > 0 && len(output.Result[0].Expressions) > 0 {
+			result = output.Result[0].Expressions[0].Value
+		}
+	}
+	if len(result) == 0 || string(result) == "null" {
+		return nil, nil
+	}
+
+	var decision opaDecision
+	if err := json.Unmarshal(result, &decision); err != nil {
+		return nil, fmt.Errorf("parsing OPA decision %s: %w", result, err)
+	}
+	switch decision.Action {
+	case policyMerge, policyPrompt, policySkip:
+		return &decision, nil
+	default:
+		return nil, fmt.Errorf("OPA decision %s has unknown action %q", result, decision.Action)
+	}
+}
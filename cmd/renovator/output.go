@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// validateOutput checks an -output format: text, csv or urls, optionally limited to the PRs with a decision as
+// in urls:merged.
+func validateOutput(output string) error {
+	format, only, limited := strings.Cut(output, ":")
+	switch {
+	case (format == "text" || format == "csv") && !limited:
+		return nil
+	case format == "urls" && (!limited || only == string(decisionMerged) || only == string(decisionSkipped) ||
+		only == string(decisionFailed)):
+		return nil
+	}
+	return fmt.Errorf("unknown output format %q", output)
+}
+
+// writeOutput writes the report in an -output format other than text, which is printed as PRs are processed.
+func (r *report) writeOutput(w io.Writer, output string) error {
+	format, only, _ := strings.Cut(output, ":")
+	switch format {
+	case "csv":
+		return r.writeCSV(w)
+	case "urls":
+		return r.writeURLs(w, decision(only))
+	}
+	return nil
+}
+
+// writeURLs writes the URLs of the PRs in the report one per line, limited to those with decision only when set.
+func (r *report) writeURLs(w io.Writer, only decision) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, result := range r.results {
+		if only != "" && result.Decision != only {
+			continue
+		}
+		if _, err := fmt.Fprintln(w, result.PR.URL); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeCSV writes one row per PR of the report with the dependency update, decision and reason, and the workflow
+// run triggered after merging.
+func (r *report) writeCSV(w io.Writer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	out := csv.NewWriter(w)
+	_ = out.Write([]string{"org", "repo", "number", "dependency", "from", "to", "decision", "reason", "url", "run", "code"})
+	for _, result := range r.results {
+		u := parseUpdate(result.PR)
+		_ = out.Write([]string{
+			result.PR.Org, result.PR.Repo, strconv.Itoa(result.PR.Number),
+			u.Dependency, u.From, u.To,
+			string(result.Decision), result.Reason, result.PR.URL, result.RunURL, string(result.Code),
+		})
+	}
+	out.Flush()
+	return out.Error()
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateOutput(t *testing.T) {
+	tests := []struct {
+		output  string
+		wantErr bool
+	}{
+		{"text", false},
+		{"csv", false},
+		{"urls", false},
+		{"urls:merged", false},
+		{"urls:skipped", false},
+		{"urls:failed", false},
+		{"urls:", true},
+		{"urls:approved", true},
+		{"csv:merged", true},
+		{"text:merged", true},
+		{"json", true},
+		{"", true},
+	}
+	for _, test := range tests {
+		if err := validateOutput(test.output); (err != nil) != test.wantErr {
+			t.Errorf("validateOutput(%q) returned %v, want an error %v", test.output, err, test.wantErr)
+		}
+	}
+}
+
+func TestWriteURLs(t *testing.T) {
+	r := &report{results: []prResult{
+		{PR: &PullRequest{URL: "https://example.com/acme/svc/pull/1"}, Decision: decisionMerged},
+		{PR: &PullRequest{URL: "https://example.com/acme/svc/pull/2"}, Decision: decisionSkipped},
+		{PR: &PullRequest{URL: "https://example.com/acme/svc/pull/3"}, Decision: decisionMerged},
+	}}
+	tests := []struct {
+		output string
+		want   string
+	}{
+		{"urls", "https://example.com/acme/svc/pull/1\nhttps://example.com/acme/svc/pull/2\nhttps://example.com/acme/svc/pull/3\n"},
+		{"urls:merged", "https://example.com/acme/svc/pull/1\nhttps://example.com/acme/svc/pull/3\n"},
+		{"urls:failed", ""},
+	}
+	for _, test := range tests {
+		var out strings.Builder
+		if err := r.writeOutput(&out, test.output); err != nil {
+			t.Fatal(err)
+		}
+		if out.String() != test.want {
+			t.Errorf("%s wrote %q, want %q", test.output, out.String(), test.want)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+)
+
+// pathPattern is a glob matching the paths of files in a repository, where * matches within a directory and **
+// across directories, e.g. services/payments/** or **/go.mod.
+type pathPattern struct {
+	glob   string
+	regexp *regexp.Regexp
+}
+
+// parsePathPatterns parses comma separated globs.
+func parsePathPatterns(value string) ([]pathPattern, error) {
+	var patterns []pathPattern
+	for _, glob := range strings.Split(value, ",") {
+		glob = strings.Trim(strings.TrimSpace(glob), "/")
+		if glob == "" {
+			continue
+		}
+		var expr strings.Builder
+		expr.WriteString("^")
+		for i := 0; i < len(glob); i++ {
+			switch {
+			case strings.HasPrefix(glob[i:], "**/"):
+				expr.WriteString("(?:.*/)?")
+				i += 2
+			case strings.HasPrefix(glob[i:], "**"):
+				expr.WriteString(".*")
+				i++
+			case glob[i] == '*':
+				expr.WriteString("[^/]*")
+			case glob[i] == '?':
+				expr.WriteString("[^/]")
+			default:
+				expr.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+			}
+		}
+		expr.WriteString("$")
+		re, err := regexp.Compile(expr.String())
+		if err != nil {
+			return nil, fmt.Errorf("invalid path %q: %w", glob, err)
+		}
+		patterns = append(patterns, pathPattern{glob: glob, regexp: re})
+	}
+	return patterns, nil
+}
+
+func (p pathPattern) match(path string) bool {
+	return p.regexp.MatchString(strings.TrimPrefix(path, "/"))
+}
+
+// filterPaths returns the PRs of prs changing a file matched by -path, so that teams owning part of a monorepo
+// only merge the updates affecting it. PRs whose files cannot be listed are left out.
+func (r *runner) filterPaths(ctx context.Context, prs []*PullRequest) ([]*PullRequest, error) {
+	if len(r.opts.paths) == 0 {
+		return prs, nil
+	}
+	provider, ok := r.provider.(changedFilesProvider)
+	if !ok {
+		return nil, fmt.Errorf("filtering PRs by path is not supported by the provider")
+	}
+	var matching []*PullRequest
+	for _, pr := range prs {
+		files, err := provider.ChangedFiles(ctx, pr)
+		if err != nil {
+			log.Printf("Error listing files changed by %s, leaving it out: %v", pr.URL, err)
+			continue
+		}
+		if changesPath(files, r.opts.paths) {
+			matching = append(matching, pr)
+		} else {
+			verbosef("PR %s changes no file in %s\n", pr.URL, formatPathPatterns(r.opts.paths))
+		}
+	}
+	return matching, nil
+}
+
+func changesPath(files []changedFile, patterns []pathPattern) bool {
+	for _, file := range files {
+		for _, pattern := range patterns {
+			if pattern.match(file.Path) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func formatPathPatterns(patterns []pathPattern) string {
+	globs := make([]string, len(patterns))
+	for i, pattern := range patterns {
+		globs[i] = pattern.glob
+	}
+	return strings.Join(globs, ", ")
+}
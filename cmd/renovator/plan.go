@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// plannedPR is a PR a saved plan acts on, at the head it was planned at.
+type plannedPR struct {
+	Target  string `json:"target"`
+	Org     string `json:"org"`
+	Repo    string `json:"repo"`
+	Number  int    `json:"number"`
+	Title   string `json:"title"`
+	URL     string `json:"url"`
+	Author  string `json:"author,omitempty"`
+	HeadSHA string `json:"headSha"`
+}
+
+// savedPlan is written by renovator plan for review, e.g. in change management, and executed by renovator apply.
+type savedPlan struct {
+	CreatedAt time.Time   `json:"createdAt"`
+	PRs       []plannedPR `json:"prs"`
+}
+
+// plan returns the statuses of the matching PRs and the PRs among them ready to merge at their current heads.
+func (r *runner) plan(ctx context.Context) ([]prStatus, []plannedPR, error) {
+	statuses, err := r.status(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	var planned []plannedPR
+	for _, s := range statuses {
+		if !s.ready() || s.HeadSHA == "" {
+			continue
+		}
+		planned = append(planned, plannedPR{
+			Target: s.Target, Org: s.Org, Repo: s.Repo, Number: s.Number, Title: s.Title, URL: s.URL,
+			Author: s.Author, HeadSHA: s.HeadSHA,
+		})
+	}
+	return statuses, planned, nil
+}
+
+func writePlan(path string, plan savedPlan) error {
+	if plan.PRs == nil {
+		plan.PRs = []plannedPR{}
+	}
+	data, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+func readPlan(path string) (*savedPlan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var plan savedPlan
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return nil, fmt.Errorf("parsing plan %s: %w", path, err)
+	}
+	return &plan, nil
+}
+
+// forTarget returns the PRs of the plan for target, which are not nil so that targets without any do not search.
+func (p *savedPlan) forTarget(target string) []plannedPR {
+	prs := []plannedPR{}
+	for _, pr := range p.PRs {
+		if pr.Target == target {
+			prs = append(prs, pr)
+		}
+	}
+	return prs
+}
+
+// findPRs returns the PRs of the plan being applied or, without one, searches for the PRs matching query.
+func (r *runner) findPRs(ctx context.Context, query SearchQuery) ([]*PullRequest, error) {
+	if r.planned == nil {
+		return r.searchPRs(ctx, query)
+	}
+	prs := make([]*PullRequest, 0, len(r.planned))
+	for _, planned := range r.planned {
+		prs = append(prs, &PullRequest{
+			Org: planned.Org, Repo: planned.Repo, Number: planned.Number, Title: planned.Title, URL: planned.URL,
+			Author: planned.Author, HeadSHA: planned.HeadSHA,
+		})
+	}
+	return prs, nil
+}
+
+// plannedHead returns the head pr was planned at, or "" when no plan is applied.
+func (r *runner) plannedHead(pr *PullRequest) string {
+	for _, planned := range r.planned {
+		if planned.URL == pr.URL {
+			return planned.HeadSHA
+		}
+	}
+	return ""
+}
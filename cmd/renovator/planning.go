@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+)
+
+// planningConfig configures making merged PRs visible in planning tools.
+type planningConfig struct {
+	// Milestone is the title of the milestone merged PRs are attached to, e.g. "Dependency updates". Repositories
+	// without a milestone of that title are left alone.
+	Milestone string `yaml:"milestone"`
+	// Project is the GitHub Project (v2) board merged PRs are added to.
+	Project *projectConfig `yaml:"project"`
+}
+
+// projectConfig is a GitHub Project (v2) board and the column merged PRs are moved to.
+type projectConfig struct {
+	// Owner is the login of the organization or user owning the project, the org of the PR by default.
+	Owner string `yaml:"owner"`
+	// Number is the number of the project in its URL.
+	Number int `yaml:"number"`
+	// Field is the single select field of the column, Status by default.
+	Field string `yaml:"field"`
+	// Value is the option of Field to set, e.g. Done. The field is left unset when empty.
+	Value string `yaml:"value"`
+}
+
+func (c *planningConfig) validate() error {
+	if c.Project != nil && c.Project.Number <= 0 {
+		return fmt.Errorf("planning project needs a number")
+	}
+	return nil
+}
+
+// planner is implemented by providers that can attach PRs to milestones and project boards.
+type planner interface {
+	// SetMilestone attaches pr to the open milestone titled title, reporting false when there is none.
+	SetMilestone(ctx context.Context, pr *PullRequest, title string) (bool, error)
+	// AddToProject adds pr to the project and sets its field to the configured value.
+	AddToProject(ctx context.Context, pr *PullRequest, project projectConfig) error
+}
+
+// updatePlanning attaches a merged PR to the configured milestone and project. Failures are logged as the merge
+// itself has succeeded.
+func (r *runner) updatePlanning(ctx context.Context, pr *PullRequest) {
+	c := r.planning
+	if c == nil {
+		return
+	}
+	provider, ok := r.provider.(planner)
+	if !ok {
+		log.Printf("Milestones and projects are not supported by the provider of %s", r.target)
+		return
+	}
+	if c.Milestone != "" {
+		found, err := provider.SetMilestone(ctx, pr, c.Milestone)
+		switch {
+		case err != nil:
+			log.Printf(paint(decisionFailed, "Error setting milestone of %s: %v"), pr.URL, err)
+		case !found:
+			verbosef("%s/%s has no open milestone %q\n", pr.Org, pr.Repo, c.Milestone)
+		default:
+			verbosef("Attached %s to milestone %s\n", pr.URL, c.Milestone)
+		}
+	}
+	if c.Project != nil {
+		project := *c.Project
+		if project.Owner == "" {
+			project.Owner = pr.Org
+		}
+		if project.Field == "" {
+			project.Field = "Status"
+		}
+		if err := provider.AddToProject(ctx, pr, project); err != nil {
+			log.Printf(paint(decisionFailed, "Error adding %s to project %s/%d: %v"), pr.URL, project.Owner, project.Number, err)
+		} else {
+			verbosef("Added %s to project %s/%d\n", pr.URL, project.Owner, project.Number)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// defaultPluginTimeout bounds a plugin invocation unless the plugin configures its own timeout.
+const defaultPluginTimeout = 30 * time.Second
+
+// pluginConfig is an external executable consulted before approving and before merging each PR.
+//
+// The plugin is given a pluginRequest as JSON on stdin and must print a pluginResponse as JSON on stdout. Exiting
+// with a non-zero status, printing anything else or timing out fails the PR.
+type pluginConfig struct {
+	Name    string        `yaml:"name"`
+	Command []string      `yaml:"command"`
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// pluginRequest is what a plugin is asked about. Phase is approve or merge.
+type pluginRequest struct {
+	Phase  string   `json:"phase"`
+	Target string   `json:"target"`
+	PR     pluginPR `json:"pr"`
+	Update *update  `json:"update,omitempty"`
+}
+
+type pluginPR struct {
+	Org     string `json:"org"`
+	Repo    string `json:"repo"`
+	Number  int    `json:"number"`
+	Title   string `json:"title"`
+	Body    string `json:"body"`
+	URL     string `json:"url"`
+	HeadSHA string `json:"headSha"`
+	Author  string `json:"author,omitempty"`
+}
+
+// pluginResponse is the verdict of a plugin. Decision is allow or deny. Annotations are shown with the PR.
+type pluginResponse struct {
+	Decision    string   `json:"decision"`
+	Reason      string   `json:"reason"`
+	Annotations []string `json:"annotations"`
+}
+
+// runPlugin invokes the plugin with request.
+func runPlugin(ctx context.Context, plugin pluginConfig, request pluginRequest) (*pluginResponse, error) {
+	if len(plugin.Command) == 0 {
+		return nil, fmt.Errorf("plugin %s has no command", plugin.Name)
+	}
+	timeout := plugin.Timeout
+	if timeout <= 0 {
+		timeout = defaultPluginTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	input, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, plugin.Command[0], plugin.Command[1:]...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return nil, fmt.Errorf("plugin %s: %w: %s", plugin.Name, err, message)
+		}
+		return nil, fmt.Errorf("plugin %s: %w", plugin.Name, err)
+	}
+
+	var response pluginResponse
+	if err := json.Unmarshal(stdout.Bytes(), &response); err != nil {
+		return nil, fmt.Errorf("plugin %s: parsing response: %w", plugin.Name, err)
+	}
+	if response.Decision != "allow" && response.Decision != "deny" {
+		return nil, fmt.Errorf("plugin %s: unknown decision %q", plugin.Name, response.Decision)
+	}
+	return &response, nil
+}
+
+// consultPlugins asks the plugins whether pr may proceed with phase, recording the PR as skipped when a plugin
+// denies it or as failed when a plugin fails. It returns whether all plugins allowed the PR.
+func (r *runner) consultPlugins(ctx context.Context, phase string, pr, prDetails *PullRequest) bool {
+	if len(r.plugins) == 0 {
+		return true
+	}
+	request := pluginRequest{
+		Phase:  phase,
+		Target: r.target,
+		PR: pluginPR{
+			Org:     pr.Org,
+			Repo:    pr.Repo,
+			Number:  pr.Number,
+			Title:   pr.Title,
+			Body:    prDetails.Body,
+			URL:     pr.URL,
+			HeadSHA: prDetails.HeadSHA,
+			Author:  pr.Author,
+		},
+	}
+	if u := parseUpdate(prDetails); u.Dependency != "" {
+		request.Update = &u
+	}
+
+	for _, plugin := range r.plugins {
+		response, err := runPlugin(ctx, plugin, request)
+		if err != nil {
+			r.printf(paint(decisionFailed, msg("Error consulting %v"))+"\n", err)
+			r.record(pr, decisionFailed, err.Error())
+			return false
+		}
+		for _, annotation := range response.Annotations {
+			r.printf("%s: %s\n", plugin.Name, annotation)
+		}
+		if response.Decision == "deny" {
+			r.printf(paint(decisionSkipped, msg("PR %s denied by plugin %s: %s"))+"\n", pr.Title, plugin.Name, response.Reason)
+			r.record(pr, decisionSkipped, "denied by plugin "+plugin.Name)
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+)
+
+// policy actions deciding what happens to a PR that is ready to be merged.
+const (
+	policyMerge  = "merge"
+	policyPrompt = "prompt"
+	policySkip   = "skip"
+)
+
+// policyConfig is a CEL expression and the action taken on the PRs it matches. The expression can use:
+//
+//   - update.type (major, minor, patch or "" when unknown), update.dependency, update.from and update.to
+//   - repo.org, repo.name and repo.topics (empty for providers without topics)
+//   - pr.number, pr.title, pr.body, pr.url, pr.createdAt and pr.author (the author of -a it was found for)
+type policyConfig struct {
+	When   string `yaml:"when"`
+	Action string `yaml:"action"`
+}
+
+// policy is a compiled policyConfig.
+type policy struct {
+	policyConfig
+	program cel.Program
+}
+
+// topicsProvider is implemented by providers that can list the topics of a repository.
+type topicsProvider interface {
+	RepoTopics(ctx context.Context, org, repo string) ([]string, error)
+}
+
+// compilePolicies compiles the policy expressions, checking they evaluate to a boolean.
+func compilePolicies(configs []policyConfig) ([]policy, error) {
+	if len(configs) == 0 {
+		return nil, nil
+	}
+	env, err := cel.NewEnv(
+		cel.Variable("update", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("repo", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("pr", cel.MapType(cel.StringType, cel.DynType)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	policies := make([]policy, 0, len(configs))
+	for _, c := range configs {
+		switch c.Action {
+		case policyMerge, policyPrompt, policySkip:
+		default:
+			return nil, fmt.Errorf("policy %q: unknown action %q, expected merge, prompt or skip", c.When, c.Action)
+		}
+		ast, issues := env.Compile(c.When)
+		if issues != nil && issues.Err() != nil {
+			return nil, fmt.Errorf("policy %q: %w", c.When, issues.Err())
+		}
+		if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+			return nil, fmt.Errorf("policy %q evaluates to %s instead of bool", c.When, ast.OutputType())
+		}
+		program, err := env.Program(ast)
+		if err != nil {
+			return nil, fmt.Errorf("policy %q: %w", c.When, err)
+		}
+		policies = append(policies, policy{policyConfig: c, program: program})
+	}
+	return policies, nil
+}
+
+// policyAction returns the action decided by the OPA policy or, when it leaves it undefined, by the first CEL
+// policy matching pr, trying the policies of the repo and the remembered decisions before the central ones, and the
+// reason given for it. The action is "" when nothing matches.
+func (r *runner) policyAction(ctx context.Context, pr *PullRequest, repoPolicies []policy) (action, reason string, err error) {
+	policies := slices.Concat(repoPolicies, r.remembered.list(), r.policies)
+	if len(policies) == 0 && r.opa == nil {
+		return "", "", nil
+	}
+	u := parseUpdate(pr)
+	topics := []string{}
+	// topics cost an API call per repo, so they are only fetched when a policy can use them
+	needsTopics := r.opa != nil
+	for _, p := range policies {
+		needsTopics = needsTopics || strings.Contains(p.When, "topics")
+	}
+	if needsTopics {
+		if topics, err = r.repoTopics(ctx, pr.Org, pr.Repo); err != nil {
+			return "", "", fmt.Errorf("fetching topics of %s/%s: %w", pr.Org, pr.Repo, err)
+		}
+	}
+
+	if r.opa != nil {
+		decision, err := r.opa.decide(ctx, r.opaInput(ctx, pr, u, topics))
+		if err != nil {
+			return "", "", fmt.Errorf("evaluating OPA policy: %w", err)
+		}
+		if decision != nil {
+			verbosef("OPA policy decided: %s\n", decision.Action)
+			return decision.Action, decision.Reason, nil
+		}
+	}
+
+	vars := policyVars(pr, u, topics)
+	for _, p := range policies {
+		matched, err := p.matches(ctx, vars)
+		if err != nil {
+			return "", "", err
+		}
+		if matched {
+			verbosef("Policy %q matched: %s\n", p.When, p.Action)
+			return p.Action, "", nil
+		}
+	}
+	return "", "", nil
+}
+
+// policyVars returns the variables policy expressions are evaluated with for pr.
+func policyVars(pr *PullRequest, u update, topics []string) map[string]any {
+	return map[string]any{
+		"update": map[string]any{
+			"type":       u.kind(),
+			"dependency": u.Dependency,
+			"from":       u.From,
+			"to":         u.To,
+		},
+		"repo": map[string]any{
+			"org":    pr.Org,
+			"name":   pr.Repo,
+			"topics": topics,
+		},
+		"pr": map[string]any{
+			"number":    pr.Number,
+			"title":     pr.Title,
+			"body":      pr.Body,
+			"url":       pr.URL,
+			"createdAt": pr.CreatedAt,
+			"author":    pr.Author,
+		},
+	}
+}
+
+// matches reports whether the expression of p holds for vars.
+func (p policy) matches(ctx context.Context, vars map[string]any) (bool, error) {
+	out, _, err := p.program.ContextEval(ctx, vars)
+	if err != nil {
+		return false, fmt.Errorf("evaluating policy %q: %w", p.When, err)
+	}
+	matched, ok := out.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("policy %q evaluated to %v instead of bool", p.When, out.Value())
+	}
+	return matched, nil
+}
+
+// repoTopics returns the topics of the repository, fetching them once per run.
+func (r *runner) repoTopics(ctx context.Context, org, repo string) ([]string, error) {
+	provider, ok := r.provider.(topicsProvider)
+	if !ok {
+		return []string{}, nil
+	}
+	key := org + "/" + repo
+	if topics, ok := r.topics[key]; ok {
+		return topics, nil
+	}
+	topics, err := provider.RepoTopics(ctx, org, repo)
+	if err != nil {
+		return nil, err
+	}
+	if topics == nil {
+		topics = []string{}
+	}
+	if r.topics == nil {
+		r.topics = map[string][]string{}
+	}
+	r.topics[key] = topics
+	return topics, nil
+}
+
+// applyPolicies decides whether pr needs confirming, recording it as skipped or failed when it is not to be merged
+// at all. It returns whether to proceed and whether the user has to confirm first.
+func (r *runner) applyPolicies(ctx context.Context, pr, prDetails *PullRequest, repoCfg *repoConfig) (proceed, confirm bool) {
+	action, reason, err := r.policyAction(ctx, prDetails, repoCfg.policies)
+	if err != nil {
+		log.Printf(paint(decisionFailed, "Error applying policies: %v"), err)
+		r.record(pr, decisionFailed, err.Error())
+		return false, false
+	}
+	optedOut := repoCfg.AutoMerge != nil && !*repoCfg.AutoMerge
+	if optedOut && action != policySkip {
+		action = policyPrompt
+	}
+	switch action {
+	case policyMerge:
+		return true, false
+	case policySkip:
+		if reason != "" {
+			r.printf(paint(decisionSkipped, msg("PR %s skipped by policy: %s"))+"\n", pr.Title, reason)
+		} else {
+			r.printf(paint(decisionSkipped, msg("PR %s skipped by policy"))+"\n", pr.Title)
+		}
+		r.triage(ctx, pr, "skipped by policy")
+		r.record(pr, decisionSkipped, "skipped by policy")
+		return false, false
+	case policyPrompt:
+		if r.opts.yes && optedOut {
+			r.printf(paint(decisionSkipped, msg("PR %s is in a repo opted out of auto-merging, skipping with -y"))+"\n", pr.Title)
+			r.record(pr, decisionSkipped, "repo opted out")
+			return false, false
+		}
+		if r.opts.yes {
+			r.printf(paint(decisionSkipped, msg("PR %s needs confirming by policy, skipping with -y"))+"\n", pr.Title)
+			r.triage(ctx, pr, "confirmation required by policy")
+			r.record(pr, decisionSkipped, "confirmation required by policy")
+			return false, false
+		}
+		return true, r.needsConfirming(pr)
+	default:
+		return true, !r.opts.yes && r.needsConfirming(pr)
+	}
+}
+
+// needsConfirming reports whether pr is still to be confirmed, i.e. it was neither confirmed together with others
+// nor are all remaining PRs approved.
+func (r *runner) needsConfirming(pr *PullRequest) bool {
+	return !r.confirmed[pr.URL] && !approveRemaining
+}
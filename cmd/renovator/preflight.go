@@ -0,0 +1,11 @@
+package main
+
+import (
+	"context"
+)
+
+// preflightChecker is implemented by providers that can verify, before anything is processed, that their
+// credentials identify a user and carry the permissions a run needs, or only those for reading with readOnly.
+type preflightChecker interface {
+	Preflight(ctx context.Context, target targetConfig, readOnly bool) error
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+const progressBarWidth = 30
+
+// progress draws a status line with a progress bar and the counts of evaluated PRs, updating it in place.
+type progress struct {
+	mu      sync.Mutex
+	out     io.Writer
+	total   int
+	counts  map[decision]int
+	current string
+	// active is set while a pass is tracked, log output is passed through otherwise
+	active bool
+}
+
+func newProgress(out io.Writer) *progress {
+	return &progress{out: out, counts: map[decision]int{}}
+}
+
+// start begins tracking a pass over total PRs.
+func (p *progress) start(total int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.total, p.counts, p.current, p.active = total, map[decision]int{}, "", true
+	p.render()
+}
+
+// evaluating shows pr as the PR currently being evaluated.
+func (p *progress) evaluating(pr *PullRequest) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.current = pr.Org + "/" + pr.Repo
+	p.render()
+}
+
+// done counts a PR as evaluated with decision d.
+func (p *progress) done(d decision) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.counts[d]++
+	p.render()
+}
+
+// finish ends the status line so that following output starts on a line of its own.
+func (p *progress) finish() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.current = ""
+	p.render()
+	fmt.Fprintln(p.out)
+	p.active = false
+}
+
+// Write implements io.Writer for log output, printing it above the status line.
+func (p *progress) Write(b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.active {
+		return p.out.Write(b)
+	}
+	fmt.Fprint(p.out, "\r\033[K")
+	n, err := p.out.Write(b)
+	p.render()
+	return n, err
+}
+
+func (p *progress) render() {
+	evaluated := p.counts[decisionMerged] + p.counts[decisionSkipped] + p.counts[decisionFailed]
+	filled := 0
+	if p.total > 0 {
+		filled = evaluated * progressBarWidth / p.total
+	}
+	bar := strings.Repeat("#", filled) + strings.Repeat("-", progressBarWidth-filled)
+	fmt.Fprintf(p.out, "\r\033[K[%s] "+msg("%d/%d evaluated, %s, %s, %s"), bar, evaluated, p.total,
+		paint(decisionMerged, fmt.Sprintf(msg("%d merged"), p.counts[decisionMerged])),
+		paint(decisionSkipped, fmt.Sprintf(msg("%d skipped"), p.counts[decisionSkipped])),
+		paint(decisionFailed, fmt.Sprintf(msg("%d failed"), p.counts[decisionFailed])))
+	if p.current != "" {
+		fmt.Fprintf(p.out, "  %s", p.current)
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// promptTimeout bounds how long prompts wait for an answer when non-zero, after which promptDefault applies.
+var promptTimeout time.Duration
+
+// promptDefault is the action taken on prompts left unanswered for promptTimeout: skip or approve.
+var promptDefault = "skip"
+
+var errPromptTimeout = errors.New("no answer")
+
+type stdinLine struct {
+	text string
+	err  error
+}
+
+var (
+	stdinOnce     sync.Once
+	stdinLines    chan stdinLine
+	stdinTerminal bool
+	// inputClosed is set once stdin has ended, so that later prompts do not wait for input that never comes.
+	inputClosed bool
+)
+
+// readAnswer reads a line from stdin, waiting at most promptTimeout when set. Lines are read by a single goroutine,
+// so that a prompt that timed out does not swallow the answer to the next one. On a terminal, lines are edited with
+// x/term in raw mode while waiting, which behaves the same on Windows consoles, in tmux and over SSH. When stdin ends,
+// e.g. piped input runs out or Ctrl-C or Ctrl-D is pressed at a prompt, the run is stopped like with q.
+func readAnswer() (string, error) {
+	stdinOnce.Do(func() {
+		stdinLines = make(chan stdinLine)
+		fd := int(os.Stdin.Fd())
+		stdinTerminal = term.IsTerminal(fd)
+		go readLines(stdinTerminal)
+	})
+	if inputClosed {
+		return "", io.EOF
+	}
+
+	// output written while the terminal is raw needs explicit carriage returns, so it is restored before answering
+	restore := func() {}
+	if stdinTerminal {
+		fd := int(os.Stdin.Fd())
+		if state, err := term.MakeRaw(fd); err == nil {
+			restore = func() { _ = term.Restore(fd, state) }
+		}
+	}
+	var timeout <-chan time.Time
+	if promptTimeout > 0 {
+		timeout = time.After(promptTimeout)
+	}
+	select {
+	case line, ok := <-stdinLines:
+		restore()
+		if !ok || errors.Is(line.err, io.EOF) {
+			inputClosed = true
+			quitRequested = true
+			fmt.Println("\n" + msg("No more input, stopping the run"))
+			return "", io.EOF
+		}
+		return line.text, line.err
+	case <-timeout:
+		restore()
+		fmt.Println()
+		return "", errPromptTimeout
+	}
+}
+
+// readLines sends the lines read from stdin to stdinLines until it ends.
+func readLines(terminal bool) {
+	defer close(stdinLines)
+	if terminal {
+		t := term.NewTerminal(struct {
+			io.Reader
+			io.Writer
+		}{os.Stdin, os.Stdout}, "")
+		for {
+			line, err := t.ReadLine()
+			if err != nil && !errors.Is(err, term.ErrPasteIndicator) {
+				stdinLines <- stdinLine{err: err}
+				return
+			}
+			stdinLines <- stdinLine{text: strings.TrimSpace(line)}
+		}
+	}
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil && (line == "" || !errors.Is(err, io.EOF)) {
+			stdinLines <- stdinLine{err: err}
+			return
+		}
+		stdinLines <- stdinLine{text: strings.TrimSpace(line)}
+	}
+}
+
+// timedOut reports whether err is a prompt timeout, telling the user about the action defaulted to, which is
+// promptDefault unless the prompt must not be approved unanswered.
+func timedOut(err error, action string) bool {
+	if !errors.Is(err, errPromptTimeout) {
+		return false
+	}
+	fmt.Printf(msg("No answer within %s, defaulting to %s")+"\n", promptTimeout, action)
+	return true
+}
+
+// logReadError logs an error reading an answer, unless stdin has ended, which readAnswer reports itself.
+func logReadError(what string, err error) {
+	if !errors.Is(err, io.EOF) {
+		log.Printf("Error reading %s: %v", what, err)
+	}
+}
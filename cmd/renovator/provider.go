@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// newProvider creates the named provider, authenticating with tokens from ts over the given transport.
+// baseURL is required for self-hosted platforms.
+func newProvider(name, baseURL string, ts oauth2.TokenSource, transport http.RoundTripper) (Provider, error) {
+	switch name {
+	case "github":
+		return newGitHubProvider(baseURL, bearerClient(ts, transport))
+	case "gitea", "forgejo":
+		if baseURL == "" {
+			return nil, fmt.Errorf("base-url is required for provider %s", name)
+		}
+		return newGiteaProvider(baseURL, bearerClient(ts, transport)), nil
+	case "azure-devops":
+		if baseURL == "" {
+			return nil, fmt.Errorf("base-url is required for provider %s", name)
+		}
+		return newAzureDevOpsProvider(baseURL, basicAuthClient(ts, transport)), nil
+	case "bitbucket-dc":
+		if baseURL == "" {
+			return nil, fmt.Errorf("base-url is required for provider %s", name)
+		}
+		return newBitbucketDCProvider(baseURL, bearerClient(ts, transport)), nil
+	default:
+		return nil, fmt.Errorf("unknown provider %q", name)
+	}
+}
+
+// Provider is a code hosting platform that renovator processes update PRs on.
+type Provider interface {
+	// SearchUpdatePRs returns the open update PRs matching the query.
+	SearchUpdatePRs(ctx context.Context, query SearchQuery) ([]*PullRequest, error)
+	// GetPR returns the current state of a single PR.
+	GetPR(ctx context.Context, org, repo string, number int) (*PullRequest, error)
+	// EvaluateChecks reports whether all checks on the PR head commit have succeeded.
+	EvaluateChecks(ctx context.Context, pr *PullRequest) (bool, error)
+	// Approve submits an approving review with the given comment.
+	Approve(ctx context.Context, pr *PullRequest, comment string) error
+	// Merge merges the PR using the given merge method (merge, squash or rebase).
+	Merge(ctx context.Context, pr *PullRequest, method string) error
+	// Unapprove withdraws the approval given by Approve and cancels auto-merge of the PR when it is enabled.
+	Unapprove(ctx context.Context, pr *PullRequest) error
+	// Close closes the PR without merging it.
+	Close(ctx context.Context, pr *PullRequest) error
+	// Comment adds a plain comment to the PR without reviewing it.
+	Comment(ctx context.Context, pr *PullRequest, body string) error
+}
+
+// SearchQuery describes which update PRs to look for. Repo takes precedence over User when both are set.
+type SearchQuery struct {
+	Org string
+	// User is who review is requested from, "" for all PRs of the org regardless of review requests.
+	User   string
+	Repo   string
+	Author string
+	// PageSize is the number of results to request, per page for providers paginating, zero meaning the provider
+	// default.
+	PageSize int
+}
+
+// incompleteSearchError is returned along with the PRs found by searches whose results the provider caps, e.g. at
+// 1000 on GitHub, so that reports can tell they are incomplete.
+type incompleteSearchError struct {
+	Found int
+	Total int
+}
+
+func (e *incompleteSearchError) Error() string {
+	return fmt.Sprintf("search results are capped at %d of %d PRs", e.Found, e.Total)
+}
+
+// PullRequest is the provider independent view of an update PR. Fields that require fetching PR details
+// (HeadSHA, Merged and Mergeable) are only populated by GetPR.
+type PullRequest struct {
+	Org    string
+	Repo   string
+	Number int
+	Title  string
+	// Body is the description of the PR, listing the updates for Renovate PRs.
+	Body      string
+	URL       string
+	HeadSHA   string
+	Merged    bool
+	Mergeable bool
+	CreatedAt time.Time
+	// BaseRef is the branch the PR merges into, when the provider tells.
+	BaseRef string
+	// AutoMerge is set when the provider merges the PR by itself once its requirements are met.
+	AutoMerge bool
+	// Draft is set for PRs that are not ready for review.
+	Draft bool
+	// MergeState is the state of mergeability beyond Mergeable when the provider tells, e.g. behind or blocked.
+	MergeState string
+	// Archived is set when the repository of the PR is archived.
+	Archived bool
+	// Closed is set for PRs closed without merging.
+	Closed bool
+	// HeadRef is the branch of the PR, e.g. renovate/lodash-4.x, when the provider tells.
+	HeadRef string
+	// Author is the author of -a the PR was found for, e.g. app/renovate.
+	Author string
+	// Labels are the names of the labels of the PR, when the provider tells.
+	Labels []string
+}
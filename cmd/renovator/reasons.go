@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"strings"
+)
+
+// reasonCode classifies why a PR was not merged, so that automation consuming the output can branch on it without
+// parsing the reason, which is meant for people.
+type reasonCode string
+
+const (
+	reasonAlreadyMerged        reasonCode = "ALREADY_MERGED"
+	reasonConflicts            reasonCode = "CONFLICTS"
+	reasonBehindBase           reasonCode = "BEHIND_BASE"
+	reasonChecksPending        reasonCode = "CHECKS_PENDING"
+	reasonChecksFailed         reasonCode = "CHECKS_FAILED"
+	reasonBlockedByProtection  reasonCode = "BLOCKED_BY_PROTECTION"
+	reasonApprovalsRequired    reasonCode = "APPROVALS_REQUIRED"
+	reasonDraft                reasonCode = "DRAFT"
+	reasonArchived             reasonCode = "ARCHIVED"
+	reasonRepoDisabled         reasonCode = "REPO_DISABLED"
+	reasonRepoLocked           reasonCode = "REPO_LOCKED"
+	reasonNoPushPermission     reasonCode = "NO_PUSH_PERMISSION"
+	reasonAutoMerge            reasonCode = "AUTO_MERGE"
+	reasonFrozen               reasonCode = "FROZEN"
+	reasonPolicy               reasonCode = "POLICY"
+	reasonConfirmationRequired reasonCode = "CONFIRMATION_REQUIRED"
+	reasonRepoOptedOut         reasonCode = "REPO_OPTED_OUT"
+	reasonLicenseChanged       reasonCode = "LICENSE_CHANGED"
+	reasonBreakingChange       reasonCode = "BREAKING_CHANGE"
+	reasonPlugin               reasonCode = "PLUGIN"
+	reasonUser                 reasonCode = "USER"
+	reasonNoAnswer             reasonCode = "NO_ANSWER"
+	reasonClosed               reasonCode = "CLOSED"
+	reasonDoNotMerge           reasonCode = "DO_NOT_MERGE"
+	reasonPlanChanged          reasonCode = "PLAN_CHANGED"
+	// reasonOther covers the remaining reasons, e.g. API errors.
+	reasonOther reasonCode = "OTHER"
+)
+
+// reasonCodes map the fixed reasons PRs are recorded with to their codes.
+var reasonCodes = map[string]reasonCode{
+	"already merged":                  reasonAlreadyMerged,
+	"not mergeable":                   reasonConflicts,
+	"draft":                           reasonDraft,
+	"archived":                        reasonArchived,
+	"repository disabled":             reasonRepoDisabled,
+	"repository locked":               reasonRepoLocked,
+	"no permission to merge":          reasonNoPushPermission,
+	"checks not succeeded":            reasonChecksFailed,
+	"deployment freeze":               reasonFrozen,
+	"skipped by policy":               reasonPolicy,
+	"confirmation required by policy": reasonConfirmationRequired,
+	"repo opted out":                  reasonRepoOptedOut,
+	"license changed":                 reasonLicenseChanged,
+	"breaking change":                 reasonBreakingChange,
+	"more approvals required":         reasonApprovalsRequired,
+	"waiting on auto-merge":           reasonAutoMerge,
+	"declined by user":                reasonUser,
+	"commented by user":               reasonUser,
+	"no answer":                       reasonNoAnswer,
+	"closed":                          reasonClosed,
+	"version ignored":                 reasonClosed,
+	"do not merge":                    reasonDoNotMerge,
+	"changed since planned":           reasonPlanChanged,
+}
+
+// codeOf returns the code of a result that does not carry one, which is "" for merged PRs.
+func codeOf(d decision, reason string) reasonCode {
+	switch {
+	case d == decisionMerged:
+		return ""
+	case strings.HasPrefix(reason, "denied by plugin "):
+		return reasonPlugin
+	}
+	if code, ok := reasonCodes[reason]; ok {
+		return code
+	}
+	return reasonOther
+}
+
+// checksCode tells failed checks from ones still running when the provider can list the states of the checks.
+func (r *runner) checksCode(ctx context.Context, pr *PullRequest) reasonCode {
+	provider, ok := r.provider.(checkRunsProvider)
+	if !ok {
+		return reasonChecksFailed
+	}
+	checks, err := provider.CheckRuns(ctx, pr)
+	if err != nil {
+		verbosef("Error reading the checks of %s: %v\n", pr.URL, err)
+		return reasonChecksFailed
+	}
+	code := reasonChecksFailed
+	for _, state := range checks {
+		switch state {
+		case "success", "skipped", "neutral":
+		case "queued", "in_progress", "pending", "waiting", "requested":
+			code = reasonChecksPending
+		default:
+			return reasonChecksFailed
+		}
+	}
+	return code
+}
+
+// mergeFailureCode classifies a refused merge by the unmet protection requirements found, or else by the merge
+// state the provider reported for pr.
+func mergeFailureCode(pr *PullRequest, blocked bool) reasonCode {
+	switch {
+	case pr.MergeState == "behind":
+		return reasonBehindBase
+	case blocked || pr.MergeState == "blocked":
+		return reasonBlockedByProtection
+	case pr.MergeState == "dirty":
+		return reasonConflicts
+	}
+	return reasonOther
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// rememberedDecisions are policies saved from the prompt, e.g. to always merge patch updates of a dependency.
+// Later runs apply them after the policies of the repo and before the configured ones. A nil value remembers
+// nothing.
+type rememberedDecisions struct {
+	path string
+
+	mu       sync.Mutex
+	configs  []policyConfig
+	policies []policy
+}
+
+// loadRememberedDecisions reads the decisions remembered in the decisions file next to the state file, or returns
+// nil without a state file.
+func loadRememberedDecisions(stateFile string) (*rememberedDecisions, error) {
+	if stateFile == "" {
+		return nil, nil
+	}
+	d := &rememberedDecisions{path: filepath.Join(filepath.Dir(stateFile), "decisions.yaml")}
+	data, err := os.ReadFile(d.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return d, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, &d.configs); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", d.path, err)
+	}
+	if d.policies, err = compilePolicies(d.configs); err != nil {
+		return nil, fmt.Errorf("%s: %w", d.path, err)
+	}
+	return d, nil
+}
+
+func (d *rememberedDecisions) list() []policy {
+	if d == nil {
+		return nil
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.policies
+}
+
+// remember adds the decision, applying it from now on, and saves it.
+func (d *rememberedDecisions) remember(decision policyConfig) error {
+	if d == nil {
+		return fmt.Errorf("remembering decisions needs a state file (-state-file)")
+	}
+	compiled, err := compilePolicies([]policyConfig{decision})
+	if err != nil {
+		return err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	configs := append(slices.Clone(d.configs), decision)
+
+	var out bytes.Buffer
+	out.WriteString("# Decisions remembered from the renovator prompt, applied before the configured policies.\n")
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(configs); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(d.path), 0o700); err != nil {
+		return err
+	}
+	if err := os.WriteFile(d.path, out.Bytes(), 0o600); err != nil {
+		return err
+	}
+	d.configs = configs
+	d.policies = append(slices.Clone(d.policies), compiled...)
+	return nil
+}
+
+// promptForRememberedDecision asks which decision to remember for the dependency of the PR, returning nil when
+// none is chosen.
+func promptForRememberedDecision(pr *PullRequest) *policyConfig {
+	dependency := parseUpdate(pr).Dependency
+	if dependency == "" {
+		fmt.Println(msg("The dependency of this PR is unknown, nothing to remember"))
+		return nil
+	}
+	is := "update.dependency == " + strconv.Quote(dependency)
+	choices := []struct {
+		label string
+		policyConfig
+	}{
+		{msg("always merge patch updates"), policyConfig{When: is + ` && update.type == "patch"`, Action: policyMerge}},
+		{msg("always merge patch and minor updates"), policyConfig{When: is + ` && update.type in ["patch", "minor"]`, Action: policyMerge}},
+		{msg("always merge all updates"), policyConfig{When: is, Action: policyMerge}},
+		{msg("always skip updates"), policyConfig{When: is, Action: policySkip}},
+	}
+	fmt.Printf(msg("Remember for %s:")+"\n", dependency)
+	for i, choice := range choices {
+		fmt.Printf("  %d. %s\n", i+1, choice.label)
+	}
+	selected := promptForSelection(msg("Select decision"), len(choices))
+	if selected < 0 {
+		return nil
+	}
+	return &choices[selected].policyConfig
+}
+
+// rememberDecision saves decision, logging failures as the PR is handled as answered regardless, and reports whether
+// the decision applies to pr, so that e.g. always merging patch updates does not merge the major update prompted for.
+func (r *runner) rememberDecision(ctx context.Context, pr *PullRequest, decision *policyConfig) bool {
+	if err := r.remembered.remember(*decision); err != nil {
+		log.Printf("Error remembering decision: %v", err)
+	} else {
+		r.printf(msg("Remembered to %s PRs matching %s")+"\n", decision.Action, decision.When)
+	}
+	compiled, err := compilePolicies([]policyConfig{*decision})
+	if err != nil {
+		log.Printf("Error evaluating decision: %v", err)
+		return false
+	}
+	topics := []string{}
+	if strings.Contains(decision.When, "topics") {
+		if topics, err = r.repoTopics(ctx, pr.Org, pr.Repo); err != nil {
+			log.Printf("Error fetching topics of %s/%s: %v", pr.Org, pr.Repo, err)
+			return false
+		}
+	}
+	matched, err := compiled[0].matches(ctx, policyVars(pr, parseUpdate(pr), topics))
+	if err != nil {
+		log.Printf("Error evaluating decision: %v", err)
+		return false
+	}
+	if !matched {
+		r.printf(msg("The remembered decision does not apply to PR %s")+"\n", pr.Title)
+	}
+	return matched
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+// update is the dependency update proposed by a Renovate PR.
+type update struct {
+	Dependency string `json:"dependency"`
+	From       string `json:"from"`
+	To         string `json:"to"`
+}
+
+var (
+	// matches default Renovate titles such as "Update dependency lodash to v4.17.21", "Update module
+	// github.com/foo/bar to v1.2.3", "Update golang Docker tag to v1.22" or "chore(deps): update actions/checkout
+	// action to v4", optionally followed by suffixes such as "(major)" or "[SECURITY]"
+	updateTitlePattern = regexp.MustCompile(`(?i)^(?:\w+(?:\([^)]*\))?!?:\s*)?update (?:dependency |module |helm release )?(\S+)(?: [\w ]+?)? to (\S+)`)
+	// titleDependencyPattern matches the dependency and target version in the differing title conventions of
+	// Renovate and Dependabot configurations, e.g. "Update module github.com/foo/bar to v1.2.3", "chore(deps): bump
+	// lodash from 4.17.20 to 4.17.21" or "fix(deps): update lodash"
+	titleDependencyPattern = regexp.MustCompile(`(?i)^(?:\w+(?:\([^)]*\))?!?:\s*)?(?:update|bump|upgrade|pin) (?:dependency |module |helm release )?(\S+)(?:.*? to v?(\S+))?`)
+	// matches version changes in the update table of Renovate PR bodies such as "`4.17.20` -> `4.17.21`"
+	versionChangePattern = regexp.MustCompile("`([^`]+)` (?:->|→) `([^`]+)`")
+	// breakingChangePattern matches markers of breaking changes in release notes such as "BREAKING", "Breaking
+	// changes" or "Migration guide"
+	breakingChangePattern = regexp.MustCompile(`\bBREAKING\b|(?i:\bbreaking changes?\b|\b(?:migration|upgrade|upgrading) guide\b)`)
+)
+
+// kind returns major, minor or patch depending on the first version component that changes, or "" when the
+// versions are unknown or not numeric.
+func (u update) kind() string {
+	from, to := versionComponents(u.From), versionComponents(u.To)
+	if len(from) == 0 || len(to) == 0 {
+		return ""
+	}
+	for i, kind := range []string{"major", "minor", "patch"} {
+		if i >= len(from) || i >= len(to) {
+			break
+		}
+		if from[i] != to[i] {
+			return kind
+		}
+	}
+	return "patch"
+}
+
+// versionComponents returns the leading numeric components of a version such as v1.2.3 or ^1.2.
+func versionComponents(version string) []string {
+	version = strings.TrimLeft(version, "^~=v<>")
+	var components []string
+	for _, part := range strings.Split(version, ".") {
+		end := 0
+		for end < len(part) && part[end] >= '0' && part[end] <= '9' {
+			end++
+		}
+		if end == 0 {
+			break
+		}
+		components = append(components, part[:end])
+		if end < len(part) {
+			break
+		}
+	}
+	return components
+}
+
+// parseUpdate extracts the dependency and versions from the title and body of a Renovate PR. Fields that cannot
+// be determined, e.g. for grouped updates, are left empty.
+func parseUpdate(pr *PullRequest) update {
+	var u update
+	if match := updateTitlePattern.FindStringSubmatch(pr.Title); match != nil {
+		u.Dependency, u.To = match[1], match[2]
+	}
+
+	var change []string
+	for _, line := range strings.Split(pr.Body, "\n") {
+		match := versionChangePattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		if change == nil || (u.Dependency != "" && strings.Contains(line, u.Dependency)) {
+			change = match
+		}
+	}
+	if change != nil {
+		u.From, u.To = change[1], change[2]
+	}
+	return u
+}
+
+// titleDependency returns the canonical identity of the update a PR title describes whatever its convention: the
+// dependency in lower case and the version it updates to without a v prefix, which is "" when the title does not
+// name one. The dependency is "" for titles not describing an update.
+func titleDependency(title string) (dependency, version string) {
+	match := titleDependencyPattern.FindStringSubmatch(strings.TrimSpace(title))
+	if match == nil {
+		return "", ""
+	}
+	return strings.ToLower(match[1]), match[2]
+}
+
+// sameDependency reports whether title describes an update of wanted, which is a title of any convention or a
+// dependency name. When wanted names a version, the title has to update to the same version.
+func sameDependency(title, wanted string) bool {
+	dependency, version := titleDependency(title)
+	if dependency == "" {
+		return false
+	}
+	wantedDependency, wantedVersion := titleDependency(wanted)
+	if wantedDependency == "" {
+		wantedDependency = strings.ToLower(strings.TrimSpace(wanted))
+	}
+	return dependency == wantedDependency && (wantedVersion == "" || wantedVersion == version)
+}
+
+// breakingChange returns the first breaking-change marker in the release notes of the body of pr, or "" when there
+// is none.
+func breakingChange(pr *PullRequest) string {
+	return breakingChangePattern.FindString(pr.Body)
+}
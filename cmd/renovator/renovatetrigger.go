@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"text/template"
+	"time"
+)
+
+// renovateTriggerTimeout bounds how long asking for a single Renovate run may take.
+const renovateTriggerTimeout = 10 * time.Second
+
+// renovateTriggerConfig configures asking Renovate for a fresh run of the repositories whose PRs it has to rebase,
+// rather than waiting for its own schedule.
+type renovateTriggerConfig struct {
+	// URL is posted to for each repository, e.g. the job API of Mend Renovate Community or Enterprise,
+	// https://renovate.example.com/api/job/add, or a webhook starting a self-hosted Renovate.
+	URL string `yaml:"url"`
+	// TokenVariable is the environment variable holding the bearer token, e.g. the API secret of the Mend server.
+	TokenVariable string `yaml:"tokenVariable"`
+	// Body is a Go template of the JSON body executed with templateData, {"repository": "{{.Org}}/{{.Repo}}"} by
+	// default.
+	Body string `yaml:"body"`
+	// StaleAfter also asks for runs of repositories with PRs left unmerged for longer than this, e.g. 336h.
+	StaleAfter time.Duration `yaml:"staleAfter"`
+}
+
+// renovateTrigger asks Renovate for fresh runs of repositories.
+type renovateTrigger struct {
+	url        string
+	token      string
+	body       *template.Template
+	staleAfter time.Duration
+}
+
+func newRenovateTrigger(c *renovateTriggerConfig) (*renovateTrigger, error) {
+	if c == nil || c.URL == "" {
+		return nil, nil
+	}
+	text := c.Body
+	if text == "" {
+		text = `{"repository": "{{.Org}}/{{.Repo}}"}`
+	}
+	body, err := template.New("body").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	if err := body.Execute(io.Discard, templateData{}); err != nil {
+		return nil, err
+	}
+	t := &renovateTrigger{url: c.URL, body: body, staleAfter: c.StaleAfter}
+	if c.TokenVariable != "" {
+		if t.token = os.Getenv(c.TokenVariable); t.token == "" {
+			return nil, fmt.Errorf("token variable %q is empty", c.TokenVariable)
+		}
+	}
+	return t, nil
+}
+
+// needsRun reports whether the PR of result needs Renovate to run again: it conflicts with or is behind its base,
+// or has been left unmerged for longer than staleAfter. A nil trigger never asks for runs.
+func (t *renovateTrigger) needsRun(result prResult) bool {
+	switch {
+	case t == nil:
+		return false
+	case result.Code == reasonConflicts || result.Code == reasonBehindBase:
+		return true
+	}
+	return t.staleAfter > 0 && result.Decision != decisionMerged && result.Code != reasonClosed &&
+		!result.PR.CreatedAt.IsZero() && time.Since(result.PR.CreatedAt) > t.staleAfter
+}
+
+// trigger asks for a Renovate run of the repository of pr.
+func (t *renovateTrigger) trigger(ctx context.Context, pr *PullRequest) error {
+	var body bytes.Buffer
+	if err := t.body.Execute(&body, newTemplateData(pr)); err != nil {
+		return err
+	}
+	header := http.Header{}
+	if t.token != "" {
+		header.Set("Authorization", "Bearer "+t.token)
+	}
+	ctx, cancel := context.WithTimeout(ctx, renovateTriggerTimeout)
+	defer cancel()
+	return postBody(ctx, t.url, body.Bytes(), header)
+}
+
+// triggerRenovate asks for a Renovate run of each repository with PRs Renovate has to rebase, once per run.
+// Failures are logged, as the PRs are merged after a later run of renovator anyway.
+func (r *runner) triggerRenovate(ctx context.Context) {
+	pending := r.renovatePending
+	r.renovatePending = nil
+	for _, pr := range pending {
+		key := pr.Org + "/" + pr.Repo
+		if r.renovateTriggered[key] {
+			continue
+		}
+		if r.renovateTriggered == nil {
+			r.renovateTriggered = map[string]bool{}
+		}
+		r.renovateTriggered[key] = true
+		if err := r.renovate.trigger(ctx, pr); err != nil {
+			log.Printf(paint(decisionFailed, "Error triggering Renovate for %s: %v"), key, err)
+			continue
+		}
+		infof(msg("Triggered a Renovate run for %s")+"\n", key)
+	}
+}
@@ -2,297 +2,869 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
-	"github.com/google/go-github/v50/github"
-	"golang.org/x/oauth2"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
+	"slices"
 	"sort"
 	"strings"
+	"syscall"
+	"text/template"
 	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/oauth2"
+	"golang.org/x/term"
 )
 
+// options holds the settings of a run as given on the command line.
+type options struct {
+	org, user, repo, author, dependency, defaultComment string
+	yes, retryUntilAllMerged, group                     bool
+	// closeUnwanted closes the matching PRs with closeComment instead of merging them.
+	closeUnwanted bool
+	closeComment  string
+	// desktopNotify shows a desktop notification when a PR is ready for confirmation.
+	desktopNotify bool
+	// sortBy orders the processed PRs and groups the results listed after each target, see sortKeys.
+	sortBy string
+	// ignoreRepoConfig disables reading the .renovator.yml of repositories.
+	ignoreRepoConfig bool
+	// skipUnchanged skips PRs whose head has not changed since the state recorded them skipped for a reason that
+	// still holds.
+	skipUnchanged bool
+	// verifyMerge is how long to wait for the checks of merge commits after processing a target, 0 to not wait.
+	verifyMerge time.Duration
+	// revertBroken is open to open a PR reverting merges whose checks fail with verifyMerge, or merge to also
+	// merge it.
+	revertBroken string
+	// resolveBotThreads resolves the review threads started by bots before approving PRs.
+	resolveBotThreads bool
+	// approvalComment renders defaultComment for each PR.
+	approvalComment *template.Template
+	// discovery is how PRs are found: search, or list to list the open PRs of each repository.
+	discovery string
+	// verifySearch re-reads the PRs found by searches, which can lag behind.
+	verifySearch bool
+	// paths narrows the PRs down to those changing a file matching one of them.
+	paths []pathPattern
+	// languages narrows the PRs down to those of repositories with one of these primary languages.
+	languages []string
+	// visibilities narrows the PRs down to those of repositories with one of these visibilities.
+	visibilities []string
+	// match is what -d is matched to and PRs are grouped by: title, or branch for the dependency in the branch name.
+	match string
+
+	// retryInterval is the wait before the first retry, multiplied by retryBackoff after each retry up to
+	// maxRetryInterval. maxRetries and maxRetryDuration bound the retries when non-zero.
+	retryInterval, maxRetryInterval, maxRetryDuration time.Duration
+	retryBackoff                                      float64
+	maxRetries                                        int
+}
+
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "keyring" {
+		if err := runKeyringCommand(os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "version" {
+		fmt.Println(versionInfo())
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "self-update" {
+		if err := runSelfUpdate(os.Args[2:]); err != nil {
+			log.Fatalf("Error updating: %v", err)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "history" {
+		if err := runHistoryCommand(os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "metrics" {
+		if err := runMetricsCommand(os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "init" {
+		if err := runInitCommand(os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	// status, campaign, audit and plan report on the matching PRs without changing them, taking the same flags as a
+	// run
+	var reportCommand, campaignDependency string
+	if len(os.Args) > 1 && (os.Args[1] == "status" || os.Args[1] == "campaign" || os.Args[1] == "audit" ||
+		os.Args[1] == "plan") {
+		reportCommand = os.Args[1]
+		os.Args = append([]string{os.Args[0]}, os.Args[2:]...)
+	}
+	if reportCommand == "campaign" {
+		if len(os.Args) < 2 || strings.HasPrefix(os.Args[1], "-") {
+			log.Fatal("Usage: renovator campaign <dependency> [flags]")
+		}
+		campaignDependency = os.Args[1]
+		os.Args = append([]string{os.Args[0]}, os.Args[2:]...)
+	}
+	// apply processes the PRs of a plan saved with renovator plan -out instead of searching
+	var applyPlan *savedPlan
+	if len(os.Args) > 1 && os.Args[1] == "apply" {
+		if len(os.Args) < 3 || strings.HasPrefix(os.Args[2], "-") {
+			log.Fatal("Usage: renovator apply <plan.json> [flags]")
+		}
+		var err error
+		if applyPlan, err = readPlan(os.Args[2]); err != nil {
+			log.Fatalf("Error reading plan: %v", err)
+		}
+		os.Args = append([]string{os.Args[0]}, os.Args[3:]...)
+	}
+	actionMode := len(os.Args) > 1 && os.Args[1] == "action"
+	if actionMode {
+		os.Args = append(append([]string{os.Args[0]}, actionArgs()...), os.Args[2:]...)
+	}
+
 	ctx := context.Background()
-	var token, tokenVariable, org, user, repo, author, dependency, defaultComment string
-	var yes, debug, retryUntilAllMerged, group bool
-
-	flag.StringVar(&token, "token", "", "GitHub token to use")
-	flag.StringVar(&tokenVariable, "token-variable", "", "Name of an environment variable to read GitHub token from")
-	flag.StringVar(&org, "o", "", "GitHub organization to renovate")
-	flag.StringVar(&user, "u", "", "GitHub user who we are renovating for")
-	flag.StringVar(&repo, "r", "", "GitHub repo name to filter by (combined with -o). If set, user filter is ignored")
-	flag.StringVar(&author, "a", "app/renovate", "The creator of renovate request")
-	flag.StringVar(&dependency, "d", "", "The dependency to renovate")
-	flag.StringVar(&defaultComment, "m", "LGTM", "The default comment for PR approvals")
-	flag.BoolVar(&yes, "y", false, "Approve all matching PR-s")
-	flag.BoolVar(&debug, "debug", false, "Enables additional output")
-	flag.BoolVar(&retryUntilAllMerged, "retry-until-all-merged", false, "Retry until all PR-s are merged")
-	flag.BoolVar(&group, "g", false, "Group PRs by dependency and select one to process")
+	var opts options
+	var profile string
+	var allowReadableTokenFile, sharedConfig, skipPreflight, daemon, noLock, showProgress, noColor, quietOutput, verbose, veryVerbose bool
+	var stateFile, schedule, quietHoursValue, freezeCalendar, lockFile, format, output, teamsWebhook, discordWebhook, webhookURL, webhookSecretVariable, messageCatalog, orgRole, pathsValue, languagesValue, visibilitiesValue, planOut string
+	var token, tokenVariable, tokenKeyring, tokenFile, tokenVault, tokenVaultField, tokenAWSSecret, tokenAWSParameter, providerName, baseURL, configFile, otlpEndpoint, recordFile, replayFile string
+
+	flag.StringVar(&token, "token", "", "GitHub token to use. Several comma separated tokens are rotated through as their rate limits run out")
+	flag.StringVar(&tokenVariable, "token-variable", "", "Name of an environment variable to read GitHub token from. Several comma separated names can be given")
+	flag.StringVar(&tokenKeyring, "token-keyring", "", "Account name to read the token from the OS keychain (stored with: renovator keyring set <account>)")
+	flag.StringVar(&tokenFile, "token-file", "", "Path of a file to read the token from, e.g. a mounted Docker or Kubernetes secret")
+	flag.BoolVar(&allowReadableTokenFile, "allow-world-readable-token-file", false, "Read the token file even if it is readable by all users")
+	flag.StringVar(&tokenVault, "token-vault", "", "Vault KV path to read the token from, e.g. secret/data/renovator (uses VAULT_ADDR and VAULT_TOKEN or VAULT_K8S_ROLE)")
+	flag.StringVar(&tokenVaultField, "token-vault-field", "token", "Field of the Vault secret holding the token")
+	flag.StringVar(&tokenAWSSecret, "token-aws-secret", "", "Name or ARN of an AWS Secrets Manager secret to read the token from")
+	flag.StringVar(&tokenAWSParameter, "token-aws-parameter", "", "Name of an AWS SSM parameter to read the token from")
+	flag.StringVar(&providerName, "provider", "github", "Code hosting provider: github, gitea, forgejo, azure-devops or bitbucket-dc")
+	flag.StringVar(&baseURL, "base-url", "", "Base URL of the provider instance (GitHub Enterprise, Gitea, Forgejo, Azure DevOps organization, Bitbucket Data Center)")
+	flag.StringVar(&configFile, "config", "", "YAML config file declaring the provider targets to process")
+	flag.StringVar(&profile, "profile", "", "Profile of the config file to use, e.g. work or oss")
+	flag.StringVar(&opts.discovery, "discovery", "search", "How to find PRs: search, or list to list the open PRs of every repository of the org, avoiding the search rate limit and indexing lag")
+	flag.BoolVar(&opts.verifySearch, "verify-search", false, "Re-read the PRs found by searching, dropping those closed or merged since, and list the open PRs of -r directly to find the ones not indexed yet")
+	flag.BoolVar(&opts.ignoreRepoConfig, "ignore-repo-config", false, "Ignore the .renovator.yml of repositories")
+	flag.BoolVar(&sharedConfig, "shared-config", false, "Use the config in .renovator/config.yaml of the org, overridden by the local config")
+	flag.StringVar(&opts.org, "o", "", "GitHub organization to renovate")
+	flag.StringVar(&opts.user, "u", "", "GitHub user who we are renovating for")
+	flag.StringVar(&opts.repo, "r", "", "GitHub repo name to filter by (combined with -o). If set, user filter is ignored")
+	flag.StringVar(&orgRole, "org-role", "", "Role the approving user must have in the org (-o): member or admin. The token's user must be an active member either way")
+	opts.author = "app/renovate"
+	flag.Var(&listFlag{value: &opts.author}, "a", "The creator of renovate request. Can be repeated or comma separated to process the PRs of several bots, e.g. -a app/renovate -a app/dependabot")
+	flag.StringVar(&opts.dependency, "d", "", "The dependency to renovate")
+	flag.StringVar(&pathsValue, "path", "", "Only process PRs changing files matching these comma separated globs, e.g. services/payments/** in a monorepo, where * matches within a directory and ** across directories")
+	flag.StringVar(&languagesValue, "language", "", "Only process PRs of repositories whose primary language is one of these comma separated languages, e.g. go,typescript")
+	flag.StringVar(&visibilitiesValue, "visibility", "", "Only process PRs of repositories with one of these comma separated visibilities: public, private or internal")
+	flag.StringVar(&planOut, "out", "", "File renovator plan saves the PRs ready to merge and their heads to, for renovator apply")
+	flag.StringVar(&opts.match, "match", "title", "What -d is matched to and -g groups by: title, or branch for the dependency Renovate names the branch after, e.g. lodash for renovate/lodash-4.x")
+	flag.StringVar(&opts.defaultComment, "m", "LGTM", "The default comment for PR approvals, a Go template such as 'Approved {{.Dependency}} {{.To}}'. @path reads it from a file and @- from stdin")
+	flag.BoolVar(&opts.yes, "y", false, "Approve all matching PR-s")
+	flag.BoolVar(&opts.closeUnwanted, "close-unwanted", false, "Close the matching PRs of the dependency (-d) with -close-comment instead of merging them, so that Renovate ignores the version (Dependabot is told to ignore it)")
+	flag.StringVar(&opts.closeComment, "close-comment", "This update is not wanted.", "Comment to close PRs with (empty for none)")
+	flag.DurationVar(&promptTimeout, "prompt-timeout", 0, "Apply -prompt-default to prompts left unanswered this long (0 to wait forever)")
+	flag.StringVar(&promptDefault, "prompt-default", "skip", "Action for prompts left unanswered for -prompt-timeout: skip or approve")
+	flag.StringVar(&format, "format", "", "Go template printed for each processed PR instead of the regular output, e.g. '{{.Repo}} {{.PR}} {{.Decision}}'. "+
+		"Fields: Target, Org, Repo, PR, Title, URL, Decision, Reason, Code, RunURL")
+	flag.StringVar(&output, "output", "text", "Output format: text, csv for one row per PR or urls[:merged|skipped|failed] for the URLs of PRs written to stdout after the run")
+	flag.BoolVar(&quietOutput, "q", false, "Only print errors")
+	flag.BoolVar(&verbose, "v", false, "Print PR details and rate limits")
+	flag.BoolVar(&veryVerbose, "vv", false, "Print PR details, rate limits and every API request")
+	flag.BoolVar(&veryVerbose, "debug", false, "Same as -vv")
+	flag.BoolVar(&opts.retryUntilAllMerged, "retry-until-all-merged", false, "Retry until all PR-s are merged")
+	flag.DurationVar(&opts.retryInterval, "retry-interval", 30*time.Second, "Wait before the first retry of retry-until-all-merged")
+	flag.Float64Var(&opts.retryBackoff, "retry-backoff", 2, "Factor the retry interval grows by after each retry")
+	flag.DurationVar(&opts.maxRetryInterval, "max-retry-interval", 5*time.Minute, "Longest wait between retries")
+	flag.IntVar(&opts.maxRetries, "max-retries", 0, "Give up retrying after this many retries (0 for no limit)")
+	flag.DurationVar(&opts.maxRetryDuration, "max-retry-duration", 0, "Give up retrying after this long (0 for no limit)")
+	flag.BoolVar(&showProgress, "progress", false, "Show a progress bar with live counts instead of per PR output (requires -y and a terminal)")
+	flag.StringVar(&messageCatalog, "messages", "", "Language of prompts and reports: a built-in catalog (en) or the path of a YAML file translating messages/en.yaml")
+	flag.BoolVar(&noColor, "no-color", false, "Disable colored output (also disabled by the NO_COLOR environment variable)")
+	flag.StringVar(&opts.sortBy, "sort", "", "Process PRs sorted by repo, dependency, age or priority and list the results grouped by it (or by status)")
+	flag.BoolVar(&opts.desktopNotify, "desktop-notify", false, "Show a desktop notification when a PR becomes ready to be confirmed, e.g. while retrying until all are merged")
+	flag.BoolVar(&opts.group, "g", false, "Group PRs by dependency and select one to process")
+	flag.BoolVar(&skipPreflight, "skip-preflight", false, "Skip verifying the token identity and scopes before processing")
+	flag.BoolVar(&daemon, "daemon", false, "Keep running and process PRs on the schedule (requires -y)")
+	flag.StringVar(&schedule, "schedule", "@every 30m", "Cron expression for when daemon mode runs passes, e.g. \"*/30 9-17 * * 1-5\"")
+	flag.StringVar(&quietHoursValue, "quiet-hours", "", "Comma separated HH:MM-HH:MM windows in which daemon mode does not run passes, e.g. 18:00-09:00")
+	flag.StringVar(&stateFile, "state-file", defaultStatePath(), "JSON lines file the results of all runs are kept in, e.g. for campaign (empty to keep none)")
+	flag.DurationVar(&opts.verifyMerge, "verify-merge", 0, "After merging, wait up to this long for the checks of the merge commits and report failing ones, e.g. 15m")
+	flag.StringVar(&opts.revertBroken, "revert-broken", "", "With -verify-merge, open a PR reverting merges whose checks fail (open) and also merge it (merge)")
+	flag.BoolVar(&opts.resolveBotThreads, "resolve-bot-threads", false, "Resolve the review threads started by bots, e.g. linters, before approving PRs")
+	flag.BoolVar(&opts.skipUnchanged, "skip-unchanged", false, "Skip PRs skipped by policy, plugins or the user in an earlier run whose head commit has not changed since")
+	flag.StringVar(&lockFile, "lock-file", "", "Lock file preventing concurrent runs (default: one per org and user or repo in the temporary directory)")
+	flag.BoolVar(&noLock, "no-lock", false, "Do not lock against concurrent runs over the same targets")
+	flag.StringVar(&freezeCalendar, "freeze-calendar", "", "URL of an iCalendar feed of deployment freezes during which PRs are evaluated but not merged")
+	flag.StringVar(&teamsWebhook, "teams-webhook", "", "Microsoft Teams webhook URL to post the results of each run to")
+	flag.StringVar(&discordWebhook, "discord-webhook", "", "Discord webhook URL to post the results of each run to")
+	flag.StringVar(&webhookURL, "webhook", "", "URL to post JSON events (run.started, pr.merged, pr.skipped, pr.failed, run.finished) to")
+	flag.StringVar(&webhookSecretVariable, "webhook-secret-variable", "", "Environment variable holding the secret webhook events are signed with")
+	flag.StringVar(&otlpEndpoint, "otlp-endpoint", "", "OTLP/HTTP endpoint (host:port or URL) to export traces to")
+	flag.StringVar(&recordFile, "record", "", "Record all GitHub API interactions to this fixture file")
+	flag.StringVar(&replayFile, "replay", "", "Replay GitHub API interactions from this fixture file instead of calling GitHub")
 	flag.Parse()
+	setupColor(noColor)
+	if messageCatalog != "" {
+		if err := loadMessages(messageCatalog); err != nil {
+			log.Fatal(err)
+		}
+	}
+	switch {
+	case veryVerbose:
+		verbosity = 2
+	case verbose:
+		verbosity = 1
+	case quietOutput:
+		verbosity = -1
+	}
+	var formatTemplate *template.Template
+	if format != "" {
+		var err error
+		if formatTemplate, err = parseFormat(format); err != nil {
+			log.Fatalf("Invalid format: %v", err)
+		}
+	}
+	if reportCommand != "" && output != "text" && output != "json" {
+		log.Fatalf("Unknown %s output format %q, expected text or json", reportCommand, output)
+	} else if err := validateOutput(output); err != nil && reportCommand == "" {
+		log.Fatal(err)
+	}
+	if (formatTemplate != nil || output != "text") && verbosity == 0 {
+		// keep stdout to the formatted output unless more is asked for
+		verbosity = -1
+	}
 
-	if token == "" && tokenVariable == "" {
-		log.Fatal("Either token or token-variable must be provided")
+	if promptDefault != "skip" && promptDefault != "approve" {
+		log.Fatalf("Unknown prompt default %q, expected skip or approve", promptDefault)
+	}
+	if opts.discovery != "search" && opts.discovery != "list" {
+		log.Fatalf("Unknown discovery %q, expected search or list", opts.discovery)
+	}
+	if opts.match != "title" && opts.match != "branch" {
+		log.Fatalf("Unknown match %q, expected title or branch", opts.match)
+	}
+	if opts.sortBy != "" && !slices.Contains(sortKeys, opts.sortBy) {
+		log.Fatalf("Unknown sort %q, expected one of %s", opts.sortBy, strings.Join(sortKeys, ", "))
+	}
+	if recordFile != "" && replayFile != "" {
+		log.Fatal("Only one of record and replay can be used")
+	}
+	if opts.defaultComment == "@-" && !opts.yes {
+		log.Fatal("Reading the approval comment from stdin requires -y as prompts read from stdin too")
+	}
+	var err error
+	if opts.approvalComment, err = parseApprovalComment(opts.defaultComment); err != nil {
+		log.Fatalf("Invalid approval comment: %v", err)
+	}
+	if opts.paths, err = parsePathPatterns(pathsValue); err != nil {
+		log.Fatal(err)
+	}
+	opts.languages = splitList(languagesValue)
+	opts.visibilities = splitList(strings.ToLower(visibilitiesValue))
+	for _, visibility := range opts.visibilities {
+		if visibility != "public" && visibility != "private" && visibility != "internal" {
+			log.Fatalf("Unknown visibility %q, expected public, private or internal", visibility)
+		}
+	}
+	if opts.revertBroken != "" && opts.revertBroken != "open" && opts.revertBroken != "merge" {
+		log.Fatalf("Unknown revert %q, expected open or merge", opts.revertBroken)
+	}
+	if opts.revertBroken != "" && opts.verifyMerge <= 0 {
+		log.Fatal("Reverting broken merges requires -verify-merge")
+	}
+	if reportCommand == "plan" && planOut == "" {
+		log.Fatal("Usage: renovator plan -out <plan.json> [flags]")
+	}
+	if applyPlan != nil {
+		if opts.closeUnwanted {
+			log.Fatal("A plan is applied by merging its PRs, not with -close-unwanted")
+		}
+		// the plan is what was reviewed, so its PRs are neither confirmed again nor narrowed down
+		opts.yes, opts.group = true, false
+		opts.dependency, opts.paths, opts.languages, opts.visibilities = "", nil, nil, nil
+	}
+	if opts.closeUnwanted && opts.dependency == "" {
+		log.Fatal("Closing unwanted PRs requires a dependency (-d)")
+	}
+	if daemon && !opts.yes {
+		log.Fatal("Daemon mode requires -y as there is nobody to confirm merges")
+	}
+	if opts.retryInterval <= 0 {
+		log.Fatalf("Retry interval must be positive, got %s", opts.retryInterval)
+	}
+	if opts.retryBackoff < 1 {
+		log.Fatalf("Retry backoff must be at least 1, got %g", opts.retryBackoff)
+	}
+	if opts.maxRetryInterval < opts.retryInterval {
+		log.Fatalf("Max retry interval %s is shorter than the retry interval %s", opts.maxRetryInterval, opts.retryInterval)
+	}
+	quiet, err := parseQuietHours(quietHoursValue)
+	if err != nil {
+		log.Fatal(err)
+	}
+	sched, err := parseSchedule(schedule)
+	if err != nil {
+		log.Fatal(err)
 	}
 
-	if token == "" {
-		token = os.Getenv(tokenVariable)
-		if token == "" {
-			log.Fatal("GitHub token is required")
+	flagTarget := targetConfig{
+		Provider: providerName,
+		BaseURL:  baseURL,
+		Org:      opts.org,
+		User:     opts.user,
+		Repo:     opts.repo,
+		Author:   opts.author,
+		OrgRole:  orgRole,
+		tokenConfig: tokenConfig{
+			TokenVariable:     tokenVariable,
+			TokenKeyring:      tokenKeyring,
+			TokenFile:         tokenFile,
+			TokenVault:        tokenVault,
+			TokenVaultField:   tokenVaultField,
+			TokenAWSSecret:    tokenAWSSecret,
+			TokenAWSParameter: tokenAWSParameter,
+		},
+	}
+	var transport http.RoundTripper = http.DefaultTransport
+	if replayFile != "" {
+		replay, err := newReplayingTransport(replayFile)
+		if err != nil {
+			log.Fatalf("Error loading replay fixture: %v", err)
 		}
+		transport = replay
+	} else if recordFile != "" {
+		transport = newRecordingTransport(transport, recordFile)
 	}
 
-	if org == "" {
-		log.Fatal("org flag is required")
+	if verbosity >= 2 {
+		transport = &loggingTransport{base: transport}
 	}
 
-	if user == "" && repo == "" {
-		log.Fatal("Either user (-u) or repo (-r) flag is required")
+	if profile != "" && configFile == "" {
+		log.Fatal("Profiles require a config file (-config)")
+	}
+	// readConfig reads the config file with the profile and shared config applied, or returns nil without one
+	readConfig := func() (*config, error) {
+		var cfg *config
+		if configFile != "" {
+			var err error
+			if cfg, err = loadConfig(configFile); err != nil {
+				return nil, err
+			}
+			if profile != "" {
+				if cfg, err = cfg.withProfile(profile); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if sharedConfig {
+			local := config{}
+			if cfg != nil {
+				local = *cfg
+			}
+			target := flagTarget
+			if len(local.Targets) > 0 {
+				target = local.resolveTarget(local.Targets[0], flagTarget)
+			}
+			var ts oauth2.TokenSource
+			if replayFile == "" {
+				var err error
+				if ts, err = resolveTokenSource(token, target, allowReadableTokenFile); err != nil {
+					return nil, fmt.Errorf("%v for %s", err, target)
+				}
+			}
+			shared, err := fetchSharedConfig(ctx, target, ts, transport)
+			if err != nil {
+				return nil, fmt.Errorf("loading shared config: %w", err)
+			}
+			merged := mergeConfig(*shared, local)
+			cfg = &merged
+		}
+		return cfg, nil
+	}
+	cfg, err := readConfig()
+	if err != nil {
+		log.Fatalf("Error loading config: %v", err)
 	}
+	targets := configTargets(cfg, flagTarget)
 
-	ts := oauth2.StaticTokenSource(
-		&oauth2.Token{AccessToken: token},
-	)
-	tc := oauth2.NewClient(ctx, ts)
+	if configFile == "" {
+		if opts.org == "" {
+			log.Fatal("org flag is required")
+		}
 
-	client := github.NewClient(tc)
+		if opts.user == "" && opts.repo == "" && reportCommand != "audit" {
+			log.Fatal("Either user (-u) or repo (-r) flag is required")
+		}
+	}
 
-	// Retry logic
-	for {
+	flags := settingsFlags{
+		freezeCalendar:        freezeCalendar,
+		teamsWebhook:          teamsWebhook,
+		discordWebhook:        discordWebhook,
+		webhookURL:            webhookURL,
+		webhookSecretVariable: webhookSecretVariable,
+	}
+	current, err := newSettings(cfg, flags)
+	if err != nil {
+		log.Fatalf("Error loading config: %v", err)
+	}
+	var alerting *alertingConfig
+	if cfg != nil {
+		alerting = cfg.Alerting
+	}
+	alerts, err := newAlertMonitor(alerting, targets)
+	if err != nil {
+		log.Fatalf("Error configuring alerting: %v", err)
+	}
 
-		// Search for PRs
-		var scopeFilter, filterDesc string
-		if repo != "" {
-			scopeFilter = fmt.Sprintf("repo:%s/%s", org, repo)
-			filterDesc = fmt.Sprintf("repo %s", repo)
-		} else {
-			scopeFilter = fmt.Sprintf("org:%s review-requested:%s", org, user)
-			filterDesc = fmt.Sprintf("user %s", user)
-		}
-		query := fmt.Sprintf("%s author:%s is:open is:pr archived:false", scopeFilter, author)
-		var searchOpts *github.SearchOptions
-		if group {
-			searchOpts = &github.SearchOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	tokenSources := make([]oauth2.TokenSource, len(targets))
+	for i, target := range targets {
+		if err := target.validate(reportCommand != "audit"); err != nil {
+			log.Fatalf("Invalid target %s: %v", target, err)
 		}
-		searchResult, _, err := client.Search.Issues(ctx, query, searchOpts)
-		if err != nil {
-			log.Fatalf("Error searching PRs: %v", err)
-		}
-
-		fmt.Printf("Found %d renovate PRs for %s\n", len(searchResult.Issues), filterDesc)
-
-		// Filter PRs by dependency if provided
-		var matchingPRs []*github.Issue
-		if dependency != "" {
-			for _, pr := range searchResult.Issues {
-				if pr.Title != nil && *pr.Title == dependency {
-					if pr.Repository != nil && pr.Repository.Name != nil && *pr.Repository.Name != "" {
-						matchingPRs = append(matchingPRs, pr)
-						fmt.Printf("Repository details: %+v\n", pr.Repository)
-					} else {
-						log.Printf("Repository name is missing for PR: %s", *pr.Title)
-					}
-				}
+		if replayFile == "" {
+			ts, err := resolveTokenSource(token, target, allowReadableTokenFile)
+			if err != nil {
+				log.Fatalf("%v for %s", err, target)
 			}
-			fmt.Printf("Found %d renovate PRs for dependency %s\n", len(matchingPRs), dependency)
+			tokenSources[i] = ts
+		}
+	}
+
+	shutdownTracing, err := setupTracing(ctx, otlpEndpoint)
+	if err != nil {
+		log.Fatalf("Error setting up tracing: %v", err)
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			log.Printf("Error flushing traces: %v", err)
+		}
+	}()
+
+	ctx, runSpan := startSpan(ctx, "run", attribute.String("org", opts.org))
+	defer runSpan.End()
+
+	var prog *progress
+	if showProgress && verbosity >= 0 {
+		if opts.yes && term.IsTerminal(int(os.Stderr.Fd())) {
+			prog = newProgress(os.Stderr)
+			log.SetOutput(prog)
 		} else {
-			matchingPRs = searchResult.Issues
-			fmt.Printf("Found %d renovate PRs\n", len(matchingPRs))
+			log.Printf("Progress bar needs -y and a terminal, showing per PR output")
 		}
+	}
 
-		// Group PRs by dependency and let user select one
-		if group && dependency == "" {
-			grouped := groupPRsByTitle(matchingPRs)
-			if len(grouped) == 0 {
-				fmt.Println("No PRs to group")
-				break
-			}
+	rep := &report{}
+	state := newStateStore(stateFile)
+	remembered, err := loadRememberedDecisions(stateFile)
+	if err != nil {
+		log.Fatalf("Error reading remembered decisions: %v", err)
+	}
+	runners := make([]*runner, len(targets))
+	for i, target := range targets {
+		usage := newCountingTransport(transport)
+
+		provider, err := newProvider(target.Provider, target.BaseURL, tokenSources[i], usage)
+		if err != nil {
+			log.Fatalf("Error creating provider: %v", err)
+		}
 
-			titles := sortedKeys(grouped)
-			fmt.Println("\nDependencies:")
-			for i, title := range titles {
-				fmt.Printf("  %d. %s (%d repos)\n", i+1, title, len(grouped[title]))
+		if checker, ok := provider.(preflightChecker); ok && !skipPreflight {
+			if err := checker.Preflight(ctx, target, reportCommand == "audit"); err != nil {
+				log.Fatalf("Preflight check failed for %s: %v", target, err)
 			}
+		}
 
-			selected := promptForSelection(len(titles))
-			if selected < 0 {
-				fmt.Println("No dependency selected, exiting")
-				break
+		targetOpts := opts
+		targetOpts.org, targetOpts.user, targetOpts.repo, targetOpts.author = target.Org, target.User, target.Repo, target.Author
+		runners[i] = &runner{provider: provider, opts: targetOpts, usage: usage, report: rep, target: target.String(), settings: current, progress: prog, format: formatTemplate, state: state, remembered: remembered}
+		if applyPlan != nil {
+			runners[i].planned = applyPlan.forTarget(runners[i].target)
+		}
+	}
+	if applyPlan != nil {
+		for _, planned := range applyPlan.PRs {
+			if !slices.ContainsFunc(runners, func(r *runner) bool { return r.target == planned.Target }) {
+				log.Fatalf("The plan acts on %s of %s, which is not a target of this run", planned.URL, planned.Target)
 			}
-			selectedTitle := titles[selected]
-			matchingPRs = grouped[selectedTitle]
-			fmt.Printf("\nProcessing dependency: %s (%d PRs)\n", selectedTitle, len(matchingPRs))
-		}
-
-		// Process each PR
-		for _, pr := range matchingPRs {
-			fmt.Printf("\nProcessing PR: %s\n", *pr.Title)
-			repoUrl := pr.GetHTMLURL()
-			fmt.Printf("Repo URL: %s\n", repoUrl)
-
-			// get third last element from the URL into repoName
-			repoName := strings.Split(repoUrl, "/")[4]
-			if repoName == "" {
-				log.Printf("Cannot get repository name for PR: %s", *pr.Title)
-				continue
+		}
+	}
+
+	switch reportCommand {
+	case "status":
+		var statuses []prStatus
+		for _, r := range runners {
+			targetStatuses, err := r.status(ctx)
+			if err != nil {
+				log.Fatalf("Error reading status of %s: %v", r.target, err)
 			}
-			prDetails, _, err := client.PullRequests.Get(ctx, org, repoName, pr.GetNumber())
+			statuses = append(statuses, targetStatuses...)
+		}
+		if err := writeStatus(os.Stdout, statuses, output); err != nil {
+			log.Fatalf("Error writing status: %v", err)
+		}
+		return
+	case "audit":
+		var entries []auditEntry
+		var incomplete []string
+		for _, r := range runners {
+			targetEntries, err := r.audit(ctx)
 			if err != nil {
-				log.Printf("Error fetching PR details: %v", err)
-				continue
+				log.Fatalf("Error auditing %s: %v", r.target, err)
 			}
-			if prDetails == nil {
-				log.Printf("PR details are nil for PR: %s", *pr.Title)
-				continue
+			entries = append(entries, targetEntries...)
+			incomplete = append(incomplete, r.incompleteSearches...)
+		}
+		if err := writeAudit(os.Stdout, entries, incomplete, output); err != nil {
+			log.Fatalf("Error writing audit: %v", err)
+		}
+		return
+	case "plan":
+		var statuses []prStatus
+		plan := savedPlan{CreatedAt: time.Now().UTC()}
+		for _, r := range runners {
+			targetStatuses, planned, err := r.plan(ctx)
+			if err != nil {
+				log.Fatalf("Error planning %s: %v", r.target, err)
 			}
+			statuses = append(statuses, targetStatuses...)
+			plan.PRs = append(plan.PRs, planned...)
+		}
+		if err := writeStatus(os.Stdout, statuses, output); err != nil {
+			log.Fatalf("Error writing status: %v", err)
+		}
+		if err := writePlan(planOut, plan); err != nil {
+			log.Fatalf("Error saving plan: %v", err)
+		}
+		infof(msg("Saved a plan of %d PRs to %s, run renovator apply %s to merge them")+"\n", len(plan.PRs), planOut, planOut)
+		return
+	case "campaign":
+		records, err := state.records()
+		if err != nil {
+			log.Fatalf("Error reading state: %v", err)
+		}
+		var repos []campaignRepo
+		for _, r := range runners {
+			targetRepos, err := r.campaign(ctx, campaignDependency, records)
 			if err != nil {
-				log.Printf("Error fetching PR details: %v", err)
-				continue
+				log.Fatalf("Error tracking campaign for %s: %v", r.target, err)
 			}
+			repos = append(repos, targetRepos...)
+		}
+		if err := writeCampaign(os.Stdout, campaignDependency, repos, output); err != nil {
+			log.Fatalf("Error writing campaign: %v", err)
+		}
+		return
+	}
 
-			if prDetails.GetMerged() {
-				fmt.Printf("PR %s is already merged\n", *pr.Title)
-				continue
-			}
+	for _, r := range runners {
+		rep.addTarget(r.target)
+	}
+	if lockFile == "" {
+		lockFile = defaultLockPath(targets)
+	}
+	// daemon mode reloads the config on SIGHUP or when the file changes
+	hangup := make(chan os.Signal, 1)
+	var configModified time.Time
+	if daemon {
+		signal.Notify(hangup, syscall.SIGHUP)
+		if info, err := os.Stat(configFile); err == nil {
+			configModified = info.ModTime()
+		}
+	}
+	reload := func() {
+		if info, err := os.Stat(configFile); err == nil {
+			configModified = info.ModTime()
+		}
+		cfg, err := readConfig()
+		if err != nil {
+			log.Printf("Error reloading config, keeping the current one: %v", err)
+			return
+		}
+		reloaded, err := newSettings(cfg, flags)
+		if err != nil {
+			log.Printf("Error reloading config, keeping the current one: %v", err)
+			return
+		}
+		if !slices.Equal(configTargets(cfg, flagTarget), targets) {
+			log.Printf("Changed targets take effect after a restart")
+		}
+		current = reloaded
+		for _, r := range runners {
+			r.settings = reloaded
+		}
+		infof("%s\n", msg("Reloaded config"))
+	}
 
-			if !prDetails.GetMergeable() {
-				fmt.Printf("PR %s cannot be merged\n", *pr.Title)
-				continue
+	failed := false
+	pass := func(ctx context.Context) {
+		if daemon {
+			select {
+			case <-hangup:
+				reload()
+			default:
+				if info, err := os.Stat(configFile); err == nil && !info.ModTime().Equal(configModified) {
+					reload()
+				}
+			}
+		}
+		passStart := time.Now()
+		rep.reset()
+		state.startRun(passStart)
+		if !noLock {
+			lock, err := acquireRunLock(lockFile)
+			if errors.Is(err, errLocked) {
+				log.Printf("Another run over these targets is in progress (pid %s), skipping", lockHolder(lockFile))
+				return
 			}
-
-			// Check if all checks are successful
-			checks, _, err := client.Checks.ListCheckRunsForRef(ctx, org, repoName, prDetails.Head.GetSHA(), nil)
 			if err != nil {
-				log.Printf("Error fetching check runs: %v", err)
-				continue
+				log.Printf("Error locking %s: %v", lockFile, err)
+				failed = true
+				return
 			}
-
-			allChecksPassed := true
-			for _, check := range checks.CheckRuns {
-				if check.GetConclusion() != "success" && check.GetConclusion() != "skipped" {
-					allChecksPassed = false
-					break
-				}
+			defer lock.release()
+		}
+		current.events.emit(webhookEvent{Type: "run.started"})
+		for _, r := range runners {
+			if quitRequested {
+				break
 			}
-
-			if !allChecksPassed {
-				fmt.Printf("PR %s has non-succeeded checks\n", *pr.Title)
-				continue
+			if len(runners) > 1 {
+				infof("\n=== %s ===\n", r.target)
 			}
-
-			// Ask for user approval before proceeding unless auto-approve
-			if yes || confirmMerge(*pr.Title) {
-				// Approve the PR
-				review := &github.PullRequestReviewRequest{
-					Body:  github.String("LGTM"),
-					Event: github.String("APPROVE"),
-				}
-				_, _, err = client.PullRequests.CreateReview(ctx, org, repoName, *pr.Number, review)
-				if err != nil {
-					log.Printf("Error approving PR: %v", err)
-					continue
-				}
-
-				// Merge the PR
-				options := &github.PullRequestOptions{
-					MergeMethod: "rebase",
-				}
-				_, _, err = client.PullRequests.Merge(ctx, org, repoName, *pr.Number, "", options)
-				if err != nil {
-					log.Printf("Error merging PR: %v", err)
-					continue
-				}
-
-				fmt.Printf("Successfully merged PR: %s\n", *pr.Title)
-			} else {
-				fmt.Printf("Skipping PR: %s\n", *pr.Title)
+			if err := r.run(ctx); err != nil {
+				log.Printf("Error processing %s: %v", r.target, err)
+				failed = true
 			}
 		}
-
-		// Check if retry is needed
-		if !retryUntilAllMerged || allPRsMerged(matchingPRs, client, ctx, org) {
-			break
+		if verbosity >= 0 {
+			if len(targets) > 1 {
+				rep.print()
+			}
+			rep.printSummary(time.Since(passStart))
+		}
+		summary := rep.summary(time.Since(passStart))
+		notifyAll(ctx, current.notifiers, summary)
+		alerts.observe(ctx, summary)
+		current.events.emit(webhookEvent{Type: "run.finished", Totals: map[string]int{
+			"merged": len(summary.Merged), "skipped": len(summary.Skipped), "failed": len(summary.Failed),
+		}})
+		if err := rep.writeOutput(os.Stdout, output); err != nil {
+			log.Printf("Error writing %s output: %v", output, err)
+			failed = true
 		}
-
-		fmt.Println("Some PR-s are not merged, retrying in 5 seconds")
-		time.Sleep(5 * time.Second)
 	}
-}
 
-func allPRsMerged(prs []*github.Issue, client *github.Client, ctx context.Context, org string) bool {
-	for _, pr := range prs {
-		repoName := strings.Split(pr.GetHTMLURL(), "/")[4]
-		prDetails, _, err := client.PullRequests.Get(ctx, org, repoName, pr.GetNumber())
-		if err != nil || prDetails == nil || !prDetails.GetMerged() {
-			return false
+	if daemon {
+		daemonCtx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+		err := runDaemon(daemonCtx, sched, quiet, pass)
+		stop()
+		if err != nil {
+			log.Fatal(err)
+		}
+	} else {
+		pass(ctx)
+	}
+	if outputPath := os.Getenv("GITHUB_OUTPUT"); actionMode && outputPath != "" {
+		if err := rep.writeActionOutputs(outputPath); err != nil {
+			log.Printf("Error writing action outputs: %v", err)
+			failed = true
 		}
 	}
-	return true
+	if failed {
+		runSpan.End()
+		_ = shutdownTracing(context.Background())
+		os.Exit(1)
+	}
+}
+
+// approveRemaining is set when the user answers a to approve and merge all remaining PRs of the run.
+var approveRemaining bool
+
+// quitRequested is set when the user answers q to stop the run.
+var quitRequested bool
+
+// mergeAnswer is the answer to whether to approve and merge a PR.
+type mergeAnswer struct {
+	approved bool
+	// method is the merge method chosen for the PR, or "" for the default one.
+	method string
+	// comment is the approval comment typed for the PR, used instead of -m when not empty.
+	comment string
+	// skipComment is left on the PR when it is skipped.
+	skipComment string
+	// undo asks to withdraw the previous approval before answering again.
+	undo bool
+	// close closes the PR with closeComment, if not empty, instead of merging it.
+	close        bool
+	closeComment string
+	// ignore has the bot not propose the version of the PR again.
+	ignore bool
+	// remember is the decision to apply to the dependency of the PR from now on, deciding the PR too if it applies.
+	remember *policyConfig
+	// timedOut is set when the prompt was left unanswered and the answer defaulted.
+	timedOut bool
 }
 
-func confirmMerge(prTitle string) bool {
-	var response string
-	fmt.Printf("Approve and merge PR '%s'? [y/N]: ", prTitle)
-	_, err := fmt.Scanln(&response)
+// confirmMerge asks whether to approve and merge the PR, taking onTimeout, skip or approve, when left unanswered.
+func confirmMerge(pr *PullRequest, onTimeout string) mergeAnswer {
+	fmt.Printf(msg("Approve and merge PR '%s'? [y/N]: "), pr.Title)
+	response, err := readAnswer()
+	if timedOut(err, onTimeout) {
+		return mergeAnswer{approved: onTimeout == "approve", timedOut: true}
+	}
 	if err != nil {
-		log.Printf("Error reading input: %v", err)
-		return false
+		logReadError("input", err)
+		return mergeAnswer{}
 	}
 	switch response {
 	case "y", "Y":
-		return true
+		return mergeAnswer{approved: true}
+	case "a", "A":
+		approveRemaining = true
+		return mergeAnswer{approved: true}
+	case "q", "Q":
+		quitRequested = true
+		return mergeAnswer{}
 	case "c", "C":
 		comment := promptForComment()
-		return confirmMergeWithComment(prTitle, comment)
+		answer := confirmMergeWithComment(pr.Title, comment, onTimeout)
+		answer.comment = comment
+		return answer
+	case "m", "M":
+		method := promptForMergeMethod()
+		if method == "" {
+			return confirmMerge(pr, onTimeout)
+		}
+		answer := confirmMergeWithMethod(pr.Title, method, onTimeout)
+		answer.method = method
+		return answer
+	case "s", "S":
+		comment := promptForSkipComment()
+		if comment == "" {
+			return confirmMerge(pr, onTimeout)
+		}
+		return mergeAnswer{skipComment: comment}
+	case "x", "X":
+		return mergeAnswer{close: true, closeComment: promptForCloseComment()}
+	case "i", "I":
+		return mergeAnswer{ignore: true}
+	case "u", "U":
+		return mergeAnswer{undo: true}
+	case "r", "R":
+		decision := promptForRememberedDecision(pr)
+		if decision == nil {
+			return confirmMerge(pr, onTimeout)
+		}
+		return mergeAnswer{remember: decision}
 	case "?":
 		showInformation()
-		return confirmMerge(prTitle)
+		return confirmMerge(pr, onTimeout)
 	default:
-		return false
+		return mergeAnswer{}
 	}
 }
 
+func promptForSkipComment() string {
+	fmt.Print(msg("Enter comment to leave on the skipped PR: "))
+	comment, err := readAnswer()
+	if err != nil {
+		logReadError("comment", err)
+		return ""
+	}
+	return comment
+}
+
+func promptForMergeMethod() string {
+	fmt.Print(msg("Merge method for this PR (merge, squash, rebase): "))
+	method, err := readAnswer()
+	if err != nil {
+		logReadError("merge method", err)
+		return ""
+	}
+	switch method {
+	case "merge", "squash", "rebase":
+		return method
+	default:
+		fmt.Println(msg("Invalid merge method"))
+		return ""
+	}
+}
+
+func confirmMergeWithMethod(prTitle, method, onTimeout string) mergeAnswer {
+	fmt.Printf(msg("Approve and %s merge PR '%s'? [y/N]: "), method, prTitle)
+	response, err := readAnswer()
+	if timedOut(err, onTimeout) {
+		return mergeAnswer{approved: onTimeout == "approve", timedOut: true}
+	}
+	if err != nil {
+		logReadError("input", err)
+		return mergeAnswer{}
+	}
+	return mergeAnswer{approved: response == "y" || response == "Y"}
+}
+
 func promptForComment() string {
-	var comment string
-	fmt.Print("Enter comment to approve the PR with: ")
-	_, err := fmt.Scanln(&comment)
+	fmt.Print(msg("Enter comment to approve the PR with: "))
+	comment, err := readAnswer()
 	if err != nil {
-		log.Printf("Error reading comment: %v", err)
+		logReadError("comment", err)
 		return "LGTM"
 	}
 	return comment
 }
 
-func confirmMergeWithComment(prTitle, comment string) bool {
-	fmt.Printf("Approve and merge PR '%s' with comment '%s'? [y/N]: ", prTitle, comment)
-	var response string
-	_, err := fmt.Scanln(&response)
+func confirmMergeWithComment(prTitle, comment, onTimeout string) mergeAnswer {
+	fmt.Printf(msg("Approve and merge PR '%s' with comment '%s'? [y/N]: "), prTitle, comment)
+	response, err := readAnswer()
+	if timedOut(err, onTimeout) {
+		return mergeAnswer{approved: onTimeout == "approve", timedOut: true}
+	}
 	if err != nil {
-		log.Printf("Error reading input: %v", err)
-		return false
+		logReadError("input", err)
+		return mergeAnswer{}
 	}
-	return response == "y" || response == "Y"
+	return mergeAnswer{approved: response == "y" || response == "Y"}
 }
 
 func showInformation() {
-	fmt.Println("y - Approve and merge this PR")
-	fmt.Println("n - Skip this PR")
-	fmt.Println("c - Approve and merge this PR with custom comment")
-	fmt.Println("? - Show this help")
+	fmt.Println(msg("y - Approve and merge this PR"))
+	fmt.Println(msg("n - Skip this PR"))
+	fmt.Println(msg("a - Approve and merge this PR and all remaining PRs of this run"))
+	fmt.Println(msg("c - Approve and merge this PR with custom comment"))
+	fmt.Println(msg("m - Approve and merge this PR with another merge method (merge, squash or rebase)"))
+	fmt.Println(msg("s - Skip this PR, leaving a comment on it, e.g. why it is not merged"))
+	fmt.Println(msg("x - Close this PR with a comment, e.g. when the update is not wanted"))
+	fmt.Println(msg("i - Ignore this version, so that Renovate or Dependabot does not propose it again"))
+	fmt.Println(msg("r - Remember a decision for the dependency of this PR, e.g. always merge its patch updates"))
+	fmt.Println(msg("u - Withdraw the approval of the previous PR, unless it is already merged"))
+	fmt.Println(msg("q - Stop the run, skipping this and all remaining PRs"))
+	fmt.Println(msg("? - Show this help"))
 }
 
-func groupPRsByTitle(prs []*github.Issue) map[string][]*github.Issue {
-	grouped := make(map[string][]*github.Issue)
+func groupPRs(prs []*PullRequest, key func(*PullRequest) string) map[string][]*PullRequest {
+	grouped := make(map[string][]*PullRequest)
 	for _, pr := range prs {
-		if pr.Title != nil {
-			grouped[*pr.Title] = append(grouped[*pr.Title], pr)
+		if k := key(pr); k != "" {
+			grouped[k] = append(grouped[k], pr)
 		}
 	}
 	return grouped
 }
 
-func sortedKeys(m map[string][]*github.Issue) []string {
+func sortedKeys(m map[string][]*PullRequest) []string {
 	keys := make([]string, 0, len(m))
 	for k := range m {
 		keys = append(keys, k)
@@ -301,19 +873,39 @@ func sortedKeys(m map[string][]*github.Issue) []string {
 	return keys
 }
 
-func promptForSelection(max int) int {
-	var input string
-	fmt.Printf("Select dependency [1-%d]: ", max)
-	_, err := fmt.Scanln(&input)
+func promptForSelection(prompt string, max int) int {
+	fmt.Printf("%s [1-%d]: ", prompt, max)
+	input, err := readAnswer()
 	if err != nil {
-		log.Printf("Error reading input: %v", err)
+		logReadError("input", err)
 		return -1
 	}
 	var selection int
 	_, err = fmt.Sscanf(input, "%d", &selection)
 	if err != nil || selection < 1 || selection > max {
-		fmt.Println("Invalid selection")
+		fmt.Println(msg("Invalid selection"))
 		return -1
 	}
 	return selection - 1
 }
+
+// listFlag is a comma separated list flag that can also be repeated, the first use replacing the default.
+type listFlag struct {
+	value *string
+	set   bool
+}
+
+func (f *listFlag) String() string {
+	if f.value == nil {
+		return ""
+	}
+	return *f.value
+}
+
+func (f *listFlag) Set(value string) error {
+	if f.set {
+		value = *f.value + "," + value
+	}
+	*f.value, f.set = value, true
+	return nil
+}
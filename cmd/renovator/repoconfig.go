@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// repoConfigPath is the file on the default branch of a repository through which its owners control renovator.
+const repoConfigPath = ".renovator.yml"
+
+// errFileNotFound is returned by fileProvider implementations for files that do not exist.
+var errFileNotFound = errors.New("file not found")
+
+// repoConfig is the content of the .renovator.yml of a repository.
+type repoConfig struct {
+	// AutoMerge set to false opts the repository out of merging without confirmation.
+	AutoMerge *bool `yaml:"autoMerge"`
+	// MergeMethod overrides the merge method: merge, squash or rebase.
+	MergeMethod string `yaml:"mergeMethod"`
+	// Policies are evaluated before the central policies.
+	Policies []policyConfig `yaml:"policies"`
+	// AfterMerge is a workflow triggered after merging a PR.
+	AfterMerge *dispatchConfig `yaml:"afterMerge"`
+	// SquashCommit overrides the templates of the commit messages of squash merges.
+	SquashCommit *commitTemplateConfig `yaml:"squashCommit"`
+
+	policies     []policy
+	squashCommit *commitTemplate
+}
+
+// repoConfig returns the .renovator.yml of the repository, reading it once per run. Repositories without one and
+// providers that cannot read files get an empty config.
+func (r *runner) repoConfig(ctx context.Context, org, repo string) (*repoConfig, error) {
+	key := org + "/" + repo
+	if c, ok := r.repoConfigs[key]; ok {
+		return c, nil
+	}
+	c := &repoConfig{}
+	if files, ok := r.provider.(fileProvider); ok && !r.opts.ignoreRepoConfig {
+		data, err := files.GetFile(ctx, org, repo, repoConfigPath)
+		switch {
+		case errors.Is(err, errFileNotFound):
+		case err != nil:
+			return nil, fmt.Errorf("reading %s of %s: %w", repoConfigPath, key, err)
+		default:
+			if err := yaml.Unmarshal(data, c); err != nil {
+				return nil, fmt.Errorf("parsing %s of %s: %w", repoConfigPath, key, err)
+			}
+			switch c.MergeMethod {
+			case "", "merge", "squash", "rebase":
+			default:
+				return nil, fmt.Errorf("%s of %s has unknown merge method %q", repoConfigPath, key, c.MergeMethod)
+			}
+			if c.AfterMerge != nil {
+				if err := c.AfterMerge.validate(); err != nil {
+					return nil, fmt.Errorf("%s of %s: %w", repoConfigPath, key, err)
+				}
+			}
+			if c.policies, err = compilePolicies(c.Policies); err != nil {
+				return nil, fmt.Errorf("%s of %s: %w", repoConfigPath, key, err)
+			}
+			if c.squashCommit, err = newCommitTemplate(c.SquashCommit); err != nil {
+				return nil, fmt.Errorf("%s of %s: %w", repoConfigPath, key, err)
+			}
+		}
+	}
+	if r.repoConfigs == nil {
+		r.repoConfigs = map[string]*repoConfig{}
+	}
+	r.repoConfigs[key] = c
+	return c, nil
+}
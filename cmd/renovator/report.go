@@ -0,0 +1,174 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// decision is the outcome of processing a single PR.
+type decision string
+
+const (
+	decisionMerged  decision = "merged"
+	decisionSkipped decision = "skipped"
+	decisionFailed  decision = "failed"
+)
+
+// skipCategories are the summary categories of skip reasons.
+var skipCategories = map[string]string{
+	"not mergeable":        "conflicts",
+	"checks not succeeded": "checks failing",
+	"declined by user":     "user skipped",
+}
+
+// prResult records what happened to a PR during a run.
+type prResult struct {
+	Target   string
+	PR       *PullRequest
+	Decision decision
+	Reason   string
+	// RunURL is the workflow run triggered after merging the PR, if any.
+	RunURL string
+	// Code classifies Reason, see reasonCode.
+	Code reasonCode
+	// Triaged is set once the PR was handed over to people at its current head, see triage.
+	Triaged bool
+}
+
+// report collects the results of all targets processed in a run.
+type report struct {
+	mu      sync.Mutex
+	targets []string
+	results []prResult
+	// broken are the merges whose checks failed with -verify-merge.
+	broken []prResult
+	// claimed are the targets processing the PRs of the run, keyed by URL.
+	claimed map[string]string
+	// escalated are the PRs failing their checks for long, listed as needing a human.
+	escalated []*PullRequest
+}
+
+func (r *report) addTarget(target string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.targets = append(r.targets, target)
+}
+
+// reset forgets the results of earlier passes.
+func (r *report) reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.results = nil
+	r.broken = nil
+	r.claimed = nil
+	r.escalated = nil
+}
+
+// claim reports whether target is the first, and so the only, target of the run to process pr.
+func (r *report) claim(target string, pr *PullRequest) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if owner, ok := r.claimed[pr.URL]; ok {
+		return owner == target
+	}
+	if r.claimed == nil {
+		r.claimed = map[string]string{}
+	}
+	r.claimed[pr.URL] = target
+	return true
+}
+
+// addBroken records a merge whose checks failed.
+func (r *report) addBroken(result prResult) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.broken = append(r.broken, result)
+}
+
+// addEscalated lists pr as needing a human.
+func (r *report) addEscalated(pr *PullRequest) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.escalated = append(r.escalated, pr)
+}
+
+// add records the result of a PR, replacing the result of an earlier pass over the same PR.
+func (r *report) add(result prResult) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, existing := range r.results {
+		if existing.Target == result.Target && existing.PR.Org == result.PR.Org &&
+			existing.PR.Repo == result.PR.Repo && existing.PR.Number == result.PR.Number {
+			r.results[i] = result
+			return
+		}
+	}
+	r.results = append(r.results, result)
+}
+
+// print outputs merged, skipped and failed counts per target.
+func (r *report) print() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	fmt.Println("\n" + msg("Report:"))
+	for _, target := range r.targets {
+		counts := map[decision]int{}
+		for _, result := range r.results {
+			if result.Target == target {
+				counts[result.Decision]++
+			}
+		}
+		fmt.Printf("  %s: %s, %s, %s\n", target,
+			paint(decisionMerged, fmt.Sprintf(msg("%d merged"), counts[decisionMerged])),
+			paint(decisionSkipped, fmt.Sprintf(msg("%d skipped"), counts[decisionSkipped])),
+			paint(decisionFailed, fmt.Sprintf(msg("%d failed"), counts[decisionFailed])))
+	}
+}
+
+// printSummary outputs the totals of the run with skipped PRs broken down by reason.
+func (r *report) printSummary(elapsed time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	counts := map[decision]int{}
+	skipped := map[string]int{}
+	for _, result := range r.results {
+		counts[result.Decision]++
+		if result.Decision == decisionSkipped {
+			category, ok := skipCategories[result.Reason]
+			if !ok {
+				category = result.Reason
+			}
+			skipped[category]++
+		}
+	}
+
+	fmt.Println("\n" + msg("Summary:"))
+	fmt.Printf("  "+msg("PRs found: %d")+"\n", len(r.results))
+	fmt.Printf("  %s\n", paint(decisionMerged, fmt.Sprintf(msg("Merged: %d"), counts[decisionMerged])))
+	fmt.Printf("  %s\n", paint(decisionSkipped, fmt.Sprintf(msg("Skipped: %d"), counts[decisionSkipped])))
+	categories := make([]string, 0, len(skipped))
+	for category := range skipped {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+	for _, category := range categories {
+		fmt.Printf("    %s: %d\n", category, skipped[category])
+	}
+	fmt.Printf("  %s\n", paint(decisionFailed, fmt.Sprintf(msg("Failed: %d"), counts[decisionFailed])))
+	if len(r.broken) > 0 {
+		fmt.Printf("  %s\n", paint(decisionFailed, fmt.Sprintf(msg("Broken after merging: %d"), len(r.broken))))
+	}
+	fmt.Printf("  "+msg("Run time: %s")+"\n", elapsed.Round(time.Millisecond))
+	if len(r.escalated) > 0 {
+		fmt.Println("\n" + msg("Needs human:"))
+		for _, pr := range r.escalated {
+			fmt.Printf("  %s/%s#%d %s, "+msg("open for %s")+"\n", pr.Org, pr.Repo, pr.Number, pr.Title,
+				formatAge(time.Since(pr.CreatedAt)))
+			fmt.Printf("    %s\n", pr.URL)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+)
+
+// repoState is what decides whether the PRs of a repository can be merged at all.
+type repoState struct {
+	Archived bool
+	Disabled bool
+	// LockReason is why the repository is locked, e.g. migrating, or "" when it is not.
+	LockReason string
+	// CanPush is set when the token may merge into the repository.
+	CanPush bool
+	// Language is the primary language of the repository, e.g. Go, or "" when it has none.
+	Language string
+	// Visibility is public, private or internal.
+	Visibility string
+}
+
+// repoStateProvider is implemented by providers that can tell the state of a repository.
+type repoStateProvider interface {
+	RepoState(ctx context.Context, org, repo string) (repoState, error)
+}
+
+// repoState returns the state of a repository, reading it once per repository and run. It returns false when the
+// provider cannot tell.
+func (r *runner) repoState(ctx context.Context, org, repo string) (repoState, bool) {
+	provider, ok := r.provider.(repoStateProvider)
+	if !ok {
+		return repoState{}, false
+	}
+	key := org + "/" + repo
+	state, ok := r.repoStates[key]
+	if !ok {
+		var err error
+		if state, err = provider.RepoState(ctx, org, repo); err != nil {
+			verbosef("Error reading the state of %s: %v\n", key, err)
+			return repoState{}, false
+		}
+		if r.repoStates == nil {
+			r.repoStates = map[string]repoState{}
+		}
+		r.repoStates[key] = state
+	}
+	return state, true
+}
+
+// repoSkipReason returns why the PRs of a repository cannot be merged, or "" when they can or the provider cannot
+// tell.
+func (r *runner) repoSkipReason(ctx context.Context, org, repo string) (reason, detail string) {
+	state, ok := r.repoState(ctx, org, repo)
+	if !ok {
+		return "", ""
+	}
+	switch {
+	case state.Archived:
+		return "archived", "is archived"
+	case state.Disabled:
+		return "repository disabled", "is disabled"
+	case state.LockReason != "":
+		return "repository locked", "is locked: " + strings.ToLower(state.LockReason)
+	case !state.CanPush:
+		return "no permission to merge", "cannot be merged into with the token, it needs write or maintain permission"
+	}
+	return "", ""
+}
+
+// preflightRepos skips the PRs of repositories they cannot be merged in, so that this is reported before prompting
+// for them rather than after approving them. It returns the remaining PRs.
+func (r *runner) preflightRepos(ctx context.Context, prs []*PullRequest) []*PullRequest {
+	var mergeable []*PullRequest
+	for _, pr := range prs {
+		reason, detail := r.repoSkipReason(ctx, pr.Org, pr.Repo)
+		if reason == "" {
+			mergeable = append(mergeable, pr)
+			continue
+		}
+		r.printf(paint(decisionSkipped, msg("Skipping PR %s: repository %s/%s %s"))+"\n", pr.URL, pr.Org, pr.Repo, detail)
+		r.record(pr, decisionSkipped, reason)
+	}
+	return mergeable
+}
+
+// filterRepos returns the PRs of prs in repositories whose primary language is one of -language and whose
+// visibility is one of -visibility, e.g. so that a sweep only touches internal Go services. PRs of repositories whose
+// state cannot be read are left out.
+func (r *runner) filterRepos(ctx context.Context, prs []*PullRequest) ([]*PullRequest, error) {
+	if len(r.opts.languages) == 0 && len(r.opts.visibilities) == 0 {
+		return prs, nil
+	}
+	if _, ok := r.provider.(repoStateProvider); !ok {
+		return nil, fmt.Errorf("filtering repositories by language or visibility is not supported by the provider")
+	}
+	var matching []*PullRequest
+	for _, pr := range prs {
+		state, ok := r.repoState(ctx, pr.Org, pr.Repo)
+		switch {
+		case !ok:
+			log.Printf("Cannot tell the language and visibility of %s/%s, leaving out %s", pr.Org, pr.Repo, pr.URL)
+		case len(r.opts.languages) > 0 && !slices.ContainsFunc(r.opts.languages, func(language string) bool {
+			return strings.EqualFold(language, state.Language)
+		}):
+			verbosef("Repository %s/%s of PR %s is in %s\n", pr.Org, pr.Repo, pr.URL, cmp.Or(state.Language, "no language"))
+		case len(r.opts.visibilities) > 0 && !slices.Contains(r.opts.visibilities, state.Visibility):
+			verbosef("Repository %s/%s of PR %s is %s\n", pr.Org, pr.Repo, pr.URL, state.Visibility)
+		default:
+			matching = append(matching, pr)
+		}
+	}
+	return matching, nil
+}
+
+// splitList splits a comma separated flag value, trimming the values, which may contain spaces such as the language
+// Jupyter Notebook.
+func splitList(value string) []string {
+	var values []string
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			values = append(values, part)
+		}
+	}
+	return values
+}
+
+// repoFilterDesc describes the repositories filterRepos keeps, e.g. "private or internal Go".
+func (r *runner) repoFilterDesc() string {
+	var parts []string
+	if len(r.opts.visibilities) > 0 {
+		parts = append(parts, strings.Join(r.opts.visibilities, " or "))
+	}
+	if len(r.opts.languages) > 0 {
+		parts = append(parts, strings.Join(r.opts.languages, " or "))
+	}
+	return strings.Join(parts, " ")
+}
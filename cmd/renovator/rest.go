@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"golang.org/x/oauth2"
+)
+
+// bearerClient returns a client authenticating requests with an "Authorization: Bearer" header holding tokens
+// from ts. A nil ts leaves requests unauthenticated, as used when replaying recorded interactions.
+func bearerClient(ts oauth2.TokenSource, base http.RoundTripper) *http.Client {
+	if ts == nil {
+		return &http.Client{Transport: base}
+	}
+	return &http.Client{Transport: withRotation(ts, &oauth2.Transport{Source: ts, Base: base})}
+}
+
+// basicAuthTransport authenticates requests with HTTP basic auth using an empty user name and the token as
+// the password, as expected for Azure DevOps personal access tokens.
+type basicAuthTransport struct {
+	source oauth2.TokenSource
+	base   http.RoundTripper
+}
+
+func basicAuthClient(ts oauth2.TokenSource, base http.RoundTripper) *http.Client {
+	if ts == nil {
+		return &http.Client{Transport: base}
+	}
+	return &http.Client{Transport: withRotation(ts, &basicAuthTransport{source: ts, base: base})}
+}
+
+func (t *basicAuthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.source.Token()
+	if err != nil {
+		return nil, err
+	}
+	req = req.Clone(req.Context())
+	req.SetBasicAuth("", token.AccessToken)
+	return t.base.RoundTrip(req)
+}
+
+// restClient is a minimal JSON-over-HTTP client used by providers without a dedicated Go SDK.
+type restClient struct {
+	baseURL string
+	http    *http.Client
+	// header is applied to every request, e.g. for authentication or API versioning.
+	header http.Header
+}
+
+func newRESTClient(baseURL string, httpClient *http.Client) *restClient {
+	return &restClient{baseURL: strings.TrimSuffix(baseURL, "/"), http: httpClient, header: http.Header{}}
+}
+
+// apiError is returned for non-2xx responses.
+type apiError struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Body       string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("%s %s: %d %s", e.Method, e.URL, e.StatusCode, e.Body)
+}
+
+// do sends a request with an optional JSON body to path (relative to the base URL) and decodes a JSON
+// response into out when out is not nil.
+func (c *restClient) do(ctx context.Context, method, path string, body, out interface{}) error {
+	data, err := c.send(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, out)
+}
+
+// send sends a request with an optional JSON body to path and returns the raw response body.
+func (c *restClient) send(ctx context.Context, method, path string, body interface{}) ([]byte, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	url := c.baseURL + path
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range c.header {
+		req.Header[k] = v
+	}
+	if req.Header.Get("Accept") == "" {
+		req.Header.Set("Accept", "application/json")
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, &apiError{Method: method, URL: url, StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(data))}
+	}
+	return data, nil
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+)
+
+// reverter is implemented by providers that can open a PR reverting a merged one.
+type reverter interface {
+	Revert(ctx context.Context, pr *PullRequest, title, body string) (*PullRequest, error)
+}
+
+// revertBroken opens a PR reverting the merge of pr with -revert-broken and, with -revert-broken merge, merges it
+// with the merge method of the repository. It returns the URL of the revert PR, or "" when none was opened.
+// Failures are logged as the broken merge is reported regardless.
+func (r *runner) revertBroken(ctx context.Context, pr *PullRequest) string {
+	if r.opts.revertBroken == "" {
+		return ""
+	}
+	provider, ok := r.provider.(reverter)
+	if !ok {
+		log.Printf("Reverting merges is not supported by the provider of %s", r.target)
+		return ""
+	}
+	title := "Revert \"" + pr.Title + "\""
+	body := fmt.Sprintf("Reverts %s, whose checks failed after renovator merged it.", pr.URL)
+	revert, err := provider.Revert(ctx, pr, title, body)
+	if err != nil {
+		log.Printf(paint(decisionFailed, "Error reverting %s: %v"), pr.URL, err)
+		return ""
+	}
+	infof(msg("Opened %s reverting %s")+"\n", revert.URL, pr.URL)
+	if r.opts.revertBroken != "merge" {
+		return revert.URL
+	}
+
+	mergeMethod := "rebase"
+	if repoCfg, err := r.repoConfig(ctx, pr.Org, pr.Repo); err == nil && repoCfg.MergeMethod != "" {
+		mergeMethod = repoCfg.MergeMethod
+	}
+	if err := r.provider.Merge(ctx, revert, mergeMethod); err != nil {
+		log.Printf(paint(decisionFailed, "Error merging revert %s, leaving it open: %v"), revert.URL, err)
+		return revert.URL
+	}
+	infof(msg("Merged revert %s")+"\n", revert.URL)
+	return revert.URL
+}
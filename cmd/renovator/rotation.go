@@ -0,0 +1,114 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"golang.org/x/oauth2"
+)
+
+// splitTokens splits a token value holding several tokens separated by commas or whitespace.
+func splitTokens(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+}
+
+// rotatingTokenSource hands out one of several tokens, moving on to the next one when the rate limit of the
+// current token is exhausted.
+type rotatingTokenSource struct {
+	mu             sync.Mutex
+	tokens         []string
+	current        int
+	exhaustedUntil []time.Time
+}
+
+func newRotatingTokenSource(tokens []string) *rotatingTokenSource {
+	return &rotatingTokenSource{tokens: tokens, exhaustedUntil: make([]time.Time, len(tokens))}
+}
+
+func (s *rotatingTokenSource) Token() (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return &oauth2.Token{AccessToken: s.tokens[s.current]}, nil
+}
+
+// rotate marks token as exhausted until reset and switches to the next token that is not exhausted. It returns
+// false when no such token is left.
+func (s *rotatingTokenSource) rotate(token string, reset time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.tokens[s.current] != token {
+		// another request has already rotated away from the exhausted token
+		return true
+	}
+	s.exhaustedUntil[s.current] = reset
+	now := time.Now()
+	for i := 1; i < len(s.tokens); i++ {
+		next := (s.current + i) % len(s.tokens)
+		if now.After(s.exhaustedUntil[next]) {
+			log.Printf("Rate limit of token %d exhausted until %s, switching to token %d",
+				s.current+1, reset.Format(time.Kitchen), next+1)
+			s.current = next
+			return true
+		}
+	}
+	return false
+}
+
+// rotatingTransport retries rate limited requests with the next token of its token source. base must
+// authenticate requests with tokens of the same source.
+type rotatingTransport struct {
+	base   http.RoundTripper
+	source *rotatingTokenSource
+}
+
+// withRotation wraps an authenticating transport with token rotation when ts holds several tokens.
+func withRotation(ts oauth2.TokenSource, base http.RoundTripper) http.RoundTripper {
+	if source, ok := ts.(*rotatingTokenSource); ok {
+		return &rotatingTransport{base: base, source: source}
+	}
+	return base
+}
+
+func (t *rotatingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for {
+		token, _ := t.source.Token()
+		resp, err := t.base.RoundTrip(req)
+		if err != nil || !rateLimitExhausted(resp) {
+			return resp, err
+		}
+		if req.Body != nil && req.GetBody == nil {
+			return resp, nil
+		}
+		if !t.source.rotate(token.AccessToken, rateLimitReset(resp)) {
+			return resp, nil
+		}
+		_ = resp.Body.Close()
+
+		req = req.Clone(req.Context())
+		if req.GetBody != nil {
+			if req.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+func rateLimitExhausted(resp *http.Response) bool {
+	return (resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests) &&
+		resp.Header.Get("X-RateLimit-Remaining") == "0"
+}
+
+func rateLimitReset(resp *http.Response) time.Time {
+	seconds, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return time.Now().Add(time.Hour)
+	}
+	return time.Unix(seconds, 0)
+}
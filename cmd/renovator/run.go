@@ -0,0 +1,615 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"text/template"
+	"time"
+
+	"github.com/google/go-github/v50/github"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// runner drives a renovation run against a single provider.
+type runner struct {
+	provider Provider
+	opts     options
+	usage    *countingTransport
+	report   *report
+	target   string
+	*settings
+	// progress replaces the per PR output with a status line when set.
+	progress *progress
+	// format replaces the per PR output with a line rendered from the template for each result when set.
+	format *template.Template
+	// timings are the time spent per phase, reported with -v.
+	timings phaseTimings
+	// repoConfigs are the .renovator.yml of repos seen during the run, keyed by org/repo.
+	repoConfigs map[string]*repoConfig
+	// topics are the topics of repos seen by policies during the run, keyed by org/repo.
+	topics map[string][]string
+	// repoStates are the states of repos seen during the run, keyed by org/repo.
+	repoStates map[string]repoState
+	// confirmed are the PRs confirmed together before processing, keyed by URL.
+	confirmed map[string]bool
+	// notifiedReady are the PRs a desktop notification has been shown for, keyed by URL.
+	notifiedReady map[string]bool
+	// lastApproved is the PR approved last, whose approval can be undone from the prompt.
+	lastApproved *PullRequest
+	// triaged are the PRs handed over to people, keyed by URL.
+	triaged map[string]bool
+	// escalated are the PRs escalated for failing their checks for long, keyed by URL.
+	escalated map[string]bool
+	// state keeps the results of all runs when set.
+	state *stateStore
+	// previous are the last results of PRs in the state, keyed by URL.
+	previous map[string]stateRecord
+	// remembered are the decisions remembered from the prompt, shared by all runners.
+	remembered *rememberedDecisions
+	// upstream fetches the changelogs of dependencies from GitHub, created when first needed.
+	upstream *github.Client
+	// toVerify are the PRs merged whose checks are waited for with -verify-merge.
+	toVerify []*PullRequest
+	// unresolvedThreads are the numbers of unresolved review threads of PRs seen during the run, keyed by URL.
+	unresolvedThreads map[string]int
+	// renovatePending are the PRs whose repositories Renovate is asked to run for after the pass.
+	renovatePending []*PullRequest
+	// renovateTriggered are the repositories Renovate was asked to run for during the run, keyed by org/repo.
+	renovateTriggered map[string]bool
+	// incompleteSearches describe the searches of the run whose results were capped by the provider.
+	incompleteSearches []string
+	// planned are the PRs of the plan applied with renovator apply, processed instead of searching when not nil.
+	planned []plannedPR
+}
+
+func (r *runner) record(pr *PullRequest, d decision, reason string) {
+	r.recordResult(prResult{Target: r.target, PR: pr, Decision: d, Reason: reason})
+}
+
+func (r *runner) recordResult(result prResult) {
+	if result.Code == "" {
+		result.Code = codeOf(result.Decision, result.Reason)
+	}
+	result.Triaged = r.triaged[result.PR.URL] || r.triagedBefore(result.PR)
+	r.report.add(result)
+	if r.progress != nil {
+		r.progress.done(result.Decision)
+	}
+	if r.format != nil {
+		printFormatted(r.format, result)
+	}
+	r.events.emit(prEvent(result))
+	r.state.add(result, r.opts.yes)
+	if r.renovate.needsRun(result) {
+		r.renovatePending = append(r.renovatePending, result.PR)
+	}
+}
+
+// printf prints per PR output unless a progress status line or formatted output is shown instead.
+func (r *runner) printf(format string, args ...any) {
+	if r.progress == nil && r.format == nil {
+		infof(format, args...)
+	}
+}
+
+// dedupe drops the PRs listed more than once, e.g. by overlapping searches, and the PRs another target processes in
+// the same run, keeping the order in which they were first found.
+func (r *runner) dedupe(prs []*PullRequest) []*PullRequest {
+	seen := map[string]bool{}
+	unique := make([]*PullRequest, 0, len(prs))
+	for _, pr := range prs {
+		if seen[pr.URL] {
+			continue
+		}
+		seen[pr.URL] = true
+		if !r.report.claim(r.target, pr) {
+			verbosef("Skipping %s, it is processed by another target\n", pr.URL)
+			continue
+		}
+		unique = append(unique, pr)
+	}
+	if dropped := len(prs) - len(unique); dropped > 0 {
+		infof(msg("Dropped %d PRs found more than once")+"\n", dropped)
+	}
+	return unique
+}
+
+func (r *runner) run(ctx context.Context) error {
+	r.repoConfigs, r.topics, r.repoStates = nil, nil, nil
+	r.renovatePending, r.renovateTriggered = nil, nil
+	previous, err := r.state.lastDecisions()
+	if err != nil && r.opts.skipUnchanged {
+		return fmt.Errorf("error reading state: %w", err)
+	} else if err != nil {
+		log.Printf("Error reading state, triaging PRs again: %v", err)
+	}
+	r.previous = previous
+	// Retry logic
+	passStart := r.usage.snapshot()
+	retryStart := time.Now()
+	retryInterval := r.opts.retryInterval
+	for pass := 1; ; pass++ {
+
+		// Search for PRs
+		query := SearchQuery{Org: r.opts.org, User: r.opts.user, Repo: r.opts.repo, Author: r.opts.author}
+		var filterDesc string
+		if r.opts.repo != "" {
+			filterDesc = fmt.Sprintf(msg("repo %s"), r.opts.repo)
+		} else {
+			filterDesc = fmt.Sprintf(msg("user %s"), r.opts.user)
+		}
+		if r.planned != nil {
+			filterDesc = msg("the plan")
+		}
+		if r.opts.group {
+			query.PageSize = 100
+		}
+		searchCtx, searchSpan := startSpan(ctx, "search", attribute.String("scope", filterDesc))
+		searchDone := r.timings.track("search")
+		prs, err := r.findPRs(searchCtx, query)
+		searchDone()
+		if err != nil {
+			spanError(searchSpan, err)
+			searchSpan.End()
+			var ssoErr *ssoRequiredError
+			if errors.As(err, &ssoErr) {
+				return err
+			}
+			return fmt.Errorf("error searching PRs: %w", err)
+		}
+		searchSpan.SetAttributes(attribute.Int("results", len(prs)))
+		searchSpan.End()
+
+		infof(msg("Found %d renovate PRs for %s")+"\n", len(prs), filterDesc)
+		prs = r.dedupe(prs)
+
+		// Filter PRs by dependency if provided
+		var matchingPRs []*PullRequest
+		if r.opts.dependency != "" {
+			for _, pr := range prs {
+				if r.matchesDependency(ctx, pr) {
+					if pr.Repo != "" {
+						matchingPRs = append(matchingPRs, pr)
+						r.printf(msg("Repository: %s/%s")+"\n", pr.Org, pr.Repo)
+					} else {
+						log.Printf("Repository name is missing for PR: %s", pr.Title)
+					}
+				}
+			}
+			infof(msg("Found %d renovate PRs for dependency %s")+"\n", len(matchingPRs), r.opts.dependency)
+		} else {
+			matchingPRs = prs
+			infof(msg("Found %d renovate PRs")+"\n", len(matchingPRs))
+		}
+		if len(r.opts.paths) > 0 {
+			if matchingPRs, err = r.filterPaths(ctx, matchingPRs); err != nil {
+				return err
+			}
+			infof(msg("Found %d renovate PRs changing %s")+"\n", len(matchingPRs), formatPathPatterns(r.opts.paths))
+		}
+		if len(r.opts.languages) > 0 || len(r.opts.visibilities) > 0 {
+			if matchingPRs, err = r.filterRepos(ctx, matchingPRs); err != nil {
+				return err
+			}
+			infof(msg("Found %d renovate PRs in %s repositories")+"\n", len(matchingPRs), r.repoFilterDesc())
+		}
+
+		// Group PRs by dependency and let user select one
+		if r.opts.group && r.opts.dependency == "" {
+			grouped := groupPRs(matchingPRs, func(pr *PullRequest) string { return r.dependencyKey(ctx, pr) })
+			if len(grouped) == 0 {
+				fmt.Println(msg("No PRs to group"))
+				break
+			}
+
+			titles := sortedKeys(grouped)
+			fmt.Println("\n" + msg("Dependencies:"))
+			for i, title := range titles {
+				fmt.Printf("  "+msg("%d. %s (%d repos)")+"\n", i+1, title, len(grouped[title]))
+			}
+
+			selected := promptForSelection(msg("Select dependency"), len(titles))
+			if selected < 0 {
+				fmt.Println(msg("No dependency selected, exiting"))
+				break
+			}
+			selectedTitle := titles[selected]
+			matchingPRs = grouped[selectedTitle]
+			fmt.Printf("\n"+msg("Processing dependency: %s (%d PRs)")+"\n", selectedTitle, len(matchingPRs))
+		}
+
+		// Process each PR
+		if r.progress != nil {
+			r.progress.start(len(matchingPRs))
+		}
+		sortPRs(matchingPRs, r.opts.sortBy)
+		toProcess := r.preflightRepos(ctx, matchingPRs)
+		if !r.opts.yes {
+			toProcess = r.confirmBatch(ctx, toProcess)
+		}
+		var group string
+		for _, pr := range toProcess {
+			if quitRequested {
+				break
+			}
+			if next := sortGroup(pr, r.opts.sortBy); next != group && next != "" {
+				r.printf("\n== %s ==\n", next)
+				group = next
+			}
+			if r.opts.closeUnwanted {
+				r.closeUnwantedPR(ctx, pr)
+			} else {
+				r.processPR(ctx, pr)
+			}
+		}
+		if r.progress != nil {
+			r.progress.finish()
+		}
+		r.triggerRenovate(ctx)
+
+		// Check if retry is needed
+		if quitRequested || r.opts.closeUnwanted || !r.opts.retryUntilAllMerged || r.allPRsMerged(ctx, matchingPRs) {
+			break
+		}
+		if freeze, frozen := r.freezes.active(time.Now()); frozen {
+			infof(msg("Deployment freeze (%s) is active, not retrying")+"\n", freeze.Reason)
+			break
+		}
+
+		reportAPIUsage(ctx, r.provider, fmt.Sprintf("pass %d", pass), r.usage.snapshot().since(passStart))
+		passStart = r.usage.snapshot()
+
+		if r.opts.maxRetries > 0 && pass > r.opts.maxRetries {
+			infof(msg("Some PR-s are not merged, giving up after %d retries")+"\n", r.opts.maxRetries)
+			break
+		}
+		if r.opts.maxRetryDuration > 0 && time.Since(retryStart)+retryInterval > r.opts.maxRetryDuration {
+			infof(msg("Some PR-s are not merged, giving up after %s")+"\n", time.Since(retryStart).Round(time.Second))
+			break
+		}
+		infof(msg("Some PR-s are not merged, retrying in %s")+"\n", retryInterval)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(retryInterval):
+		}
+		retryInterval = min(time.Duration(float64(retryInterval)*r.opts.retryBackoff), r.opts.maxRetryInterval)
+	}
+
+	r.verifyMerges(ctx)
+	if r.opts.sortBy != "" && r.progress == nil && r.format == nil {
+		r.report.printSorted(r.target, r.opts.sortBy)
+	}
+	reportAPIUsage(ctx, r.provider, r.target, r.usage.snapshot())
+	r.timings.print(r.target)
+	return nil
+}
+
+// processPR evaluates a single renovate PR and, when allowed, approves and merges it.
+func (r *runner) processPR(ctx context.Context, pr *PullRequest) {
+	ctx, span := startSpan(ctx, "evaluate PR",
+		attribute.String("pr.title", pr.Title),
+		attribute.String("pr.url", pr.URL),
+		attribute.Int("pr.number", pr.Number),
+	)
+	defer span.End()
+
+	if r.progress != nil {
+		r.progress.evaluating(pr)
+	}
+	r.printf("\n"+msg("Processing PR: %s")+"\n", pr.Title)
+	r.printf(msg("Repo URL: %s")+"\n", pr.URL)
+	if len(r.opts.authors()) > 1 {
+		r.printf(msg("Author: %s")+"\n", pr.Author)
+	}
+
+	if pr.Repo == "" {
+		log.Printf(paint(decisionFailed, "Cannot get repository name for PR: %s"), pr.Title)
+		r.record(pr, decisionFailed, "repository name missing")
+		return
+	}
+	if reason, detail := r.repoSkipReason(ctx, pr.Org, pr.Repo); reason != "" {
+		r.printf(paint(decisionSkipped, msg("Repository %s/%s %s"))+"\n", pr.Org, pr.Repo, detail)
+		r.record(pr, decisionSkipped, reason)
+		return
+	}
+	detailsDone := r.timings.track("details")
+	prDetails, err := r.provider.GetPR(ctx, pr.Org, pr.Repo, pr.Number)
+	detailsDone()
+	if err != nil {
+		spanError(span, err)
+		log.Printf(paint(decisionFailed, "Error fetching PR details: %v"), err)
+		r.record(pr, decisionFailed, err.Error())
+		return
+	}
+
+	if planned := r.plannedHead(pr); planned != "" && planned != prDetails.HeadSHA {
+		r.printf(paint(decisionFailed, msg("PR %s changed since it was planned, its head is %s instead of %s"))+"\n",
+			pr.Title, prDetails.HeadSHA, planned)
+		r.record(pr, decisionFailed, "changed since planned")
+		return
+	}
+	pr.HeadSHA = prDetails.HeadSHA
+	prDetails.Author = pr.Author
+	if previous, ok := r.unchangedSkip(prDetails); ok && !prDetails.Merged {
+		r.printf(paint(decisionSkipped, msg("PR %s is unchanged since it was skipped: %s"))+"\n", pr.Title, previous.Reason)
+		r.recordResult(prResult{Target: r.target, PR: pr, Decision: decisionSkipped, Reason: previous.Reason,
+			Code: previous.ReasonCode})
+		return
+	}
+
+	if prDetails.Merged {
+		r.printf(paint(decisionSkipped, msg("PR %s is already merged"))+"\n", pr.Title)
+		r.record(pr, decisionSkipped, "already merged")
+		return
+	}
+
+	if prDetails.Closed {
+		r.printf(paint(decisionSkipped, msg("PR %s is closed"))+"\n", pr.Title)
+		r.record(pr, decisionSkipped, "closed")
+		return
+	}
+
+	if prDetails.Archived {
+		r.printf(paint(decisionSkipped, msg("PR %s is in an archived repository"))+"\n", pr.Title)
+		r.record(pr, decisionSkipped, "archived")
+		return
+	}
+
+	if prDetails.Draft {
+		r.printf(paint(decisionSkipped, msg("PR %s is a draft"))+"\n", pr.Title)
+		r.record(pr, decisionSkipped, "draft")
+		return
+	}
+
+	if !prDetails.Mergeable {
+		r.printf(paint(decisionSkipped, msg("PR %s cannot be merged"))+"\n", pr.Title)
+		r.record(pr, decisionSkipped, "not mergeable")
+		return
+	}
+
+	// Check if all checks are successful
+	checksDone := r.timings.track("checks")
+	allChecksPassed, err := r.provider.EvaluateChecks(ctx, prDetails)
+	checksDone()
+	if err != nil {
+		log.Printf(paint(decisionFailed, "Error fetching check runs: %v"), err)
+		r.record(pr, decisionFailed, err.Error())
+		return
+	}
+
+	span.SetAttributes(attribute.Bool("checks.passed", allChecksPassed))
+	if r.progress == nil {
+		verbosef("Head %s, mergeable, checks passed: %t\n", prDetails.HeadSHA, allChecksPassed)
+	}
+	if !allChecksPassed {
+		r.printf(paint(decisionSkipped, msg("PR %s has non-succeeded checks"))+"\n", pr.Title)
+		code := r.checksCode(ctx, prDetails)
+		r.triage(ctx, pr, "checks not succeeded")
+		r.recordResult(prResult{Target: r.target, PR: pr, Decision: decisionSkipped, Reason: "checks not succeeded",
+			Code: code})
+		r.escalate(ctx, pr, code)
+		return
+	}
+
+	if freeze, frozen := r.freezes.active(time.Now()); frozen {
+		r.printf(paint(decisionSkipped, msg("PR %s is ready but not merged during deployment freeze: %s"))+"\n", pr.Title, freeze.Reason)
+		r.record(pr, decisionSkipped, "deployment freeze")
+		return
+	}
+
+	repoCfg, err := r.repoConfig(ctx, pr.Org, pr.Repo)
+	if err != nil {
+		log.Printf(paint(decisionFailed, "Error reading repo config: %v"), err)
+		r.record(pr, decisionFailed, err.Error())
+		return
+	}
+	proceed, confirm := r.applyPolicies(ctx, pr, prDetails, repoCfg)
+	if !proceed {
+		return
+	}
+	onTimeout := promptDefault
+	if r.checkDepsDev(ctx, pr) {
+		if r.opts.yes {
+			r.printf(paint(decisionSkipped, msg("PR %s changes its license, skipping with -y"))+"\n", pr.Title)
+			r.triage(ctx, pr, "license changed")
+			r.record(pr, decisionSkipped, "license changed")
+			return
+		}
+		// a license change is confirmed even when the PR was confirmed together with others, and never by a timeout
+		confirm = true
+		onTimeout = "skip"
+	}
+	if marker := breakingChange(pr); marker != "" && !confirm {
+		if r.opts.yes {
+			r.printf(paint(decisionSkipped, msg("PR %s mentions %q in its release notes, skipping with -y"))+"\n", pr.Title, marker)
+			r.triage(ctx, pr, "breaking change")
+			r.record(pr, decisionSkipped, "breaking change")
+			return
+		}
+		r.printf(msg("PR %s mentions %q in its release notes, needs confirming")+"\n", pr.Title, marker)
+		confirm = true
+	}
+	if marker := r.doNotMergeMarker(ctx, prDetails); marker != "" {
+		if r.opts.yes {
+			r.printf(paint(decisionSkipped, msg("PR %s is marked not to be merged by its %s, skipping with -y"))+"\n", pr.Title, marker)
+			r.record(pr, decisionSkipped, "do not merge")
+			return
+		}
+		r.printf(msg("PR %s is marked not to be merged by its %s, needs confirming")+"\n", pr.Title, marker)
+		confirm = true
+		onTimeout = "skip"
+	}
+
+	if !r.consultPlugins(ctx, "approve", pr, prDetails) {
+		return
+	}
+
+	r.checkThreads(ctx, prDetails)
+
+	if r.opts.desktopNotify && confirm {
+		r.notifyReady(pr)
+	}
+
+	// Ask for user approval before proceeding unless auto-approve
+	answer := mergeAnswer{approved: !confirm}
+	if confirm {
+		r.showChangelog(ctx, pr)
+		r.showLockfileChanges(ctx, prDetails)
+		answer = confirmMerge(pr, onTimeout)
+		for answer.undo || answer.remember != nil {
+			if answer.undo {
+				r.undoLastApproval(ctx)
+			} else if r.rememberDecision(ctx, prDetails, answer.remember) {
+				answer.approved = answer.remember.Action == policyMerge
+				break
+			}
+			answer = confirmMerge(pr, onTimeout)
+		}
+	}
+	if answer.approved {
+		// Approve the PR
+		approveCtx, approveSpan := startSpan(ctx, "approve")
+		approveDone := r.timings.track("approve")
+		comment := answer.comment
+		var err error
+		if comment == "" {
+			comment, err = r.approvalComment(prDetails)
+		}
+		if err == nil {
+			err = r.provider.Approve(approveCtx, prDetails, comment)
+		}
+		approveDone()
+		if err != nil {
+			spanError(approveSpan, err)
+			approveSpan.End()
+			log.Printf(paint(decisionFailed, "Error approving PR: %v"), err)
+			r.record(pr, decisionFailed, err.Error())
+			return
+		}
+		approveSpan.End()
+		r.lastApproved = prDetails
+
+		if !r.consultPlugins(ctx, "merge", pr, prDetails) {
+			return
+		}
+		if missing := r.missingApprovals(ctx, prDetails); missing > 0 {
+			r.printf(paint(decisionSkipped, msg("PR %s is approved but branch protection requires %d more approvals"))+"\n",
+				pr.Title, missing)
+			r.triage(ctx, pr, "more approvals required")
+			r.record(pr, decisionSkipped, "more approvals required")
+			return
+		}
+		// merging would fail or race the merge already scheduled by the provider
+		if prDetails.AutoMerge {
+			r.printf(paint(decisionSkipped, msg("PR %s is approved and waiting on auto-merge"))+"\n", pr.Title)
+			r.record(pr, decisionSkipped, "waiting on auto-merge")
+			return
+		}
+
+		// Merge the PR
+		mergeMethod := "rebase"
+		if repoCfg.MergeMethod != "" {
+			mergeMethod = repoCfg.MergeMethod
+		}
+		if answer.method != "" {
+			mergeMethod = answer.method
+		}
+		mergeCtx, mergeSpan := startSpan(ctx, "merge", attribute.String("merge.method", mergeMethod))
+		mergeDone := r.timings.track("merge")
+		err = r.merge(mergeCtx, prDetails, mergeMethod, repoCfg)
+		mergeDone()
+		if err != nil {
+			spanError(mergeSpan, err)
+			mergeSpan.End()
+			log.Printf(paint(decisionFailed, "Error merging PR: %v"), err)
+			blocked := r.explainBlocked(ctx, prDetails)
+			r.recordResult(prResult{Target: r.target, PR: pr, Decision: decisionFailed, Reason: err.Error(),
+				Code: mergeFailureCode(prDetails, blocked)})
+			return
+		}
+		mergeSpan.End()
+
+		r.printf(paint(decisionMerged, msg("Successfully merged PR: %s"))+"\n", pr.Title)
+		runURL := r.dispatchAfterMerge(ctx, prDetails, repoCfg)
+		if r.opts.verifyMerge > 0 {
+			r.toVerify = append(r.toVerify, prDetails)
+		}
+		r.recordResult(prResult{Target: r.target, PR: pr, Decision: decisionMerged, RunURL: runURL})
+		r.jira.recordMerge(ctx, pr)
+		r.updatePlanning(ctx, prDetails)
+	} else if quitRequested {
+		r.printf("%s\n", msg("Stopping the run"))
+	} else if answer.close {
+		r.closePR(ctx, pr, answer.closeComment)
+	} else if answer.ignore {
+		r.ignoreVersion(ctx, pr, r.opts.closeComment)
+	} else if answer.skipComment != "" {
+		if err := r.provider.Comment(ctx, prDetails, answer.skipComment); err != nil {
+			log.Printf(paint(decisionFailed, "Error commenting on PR: %v"), err)
+			r.record(pr, decisionFailed, err.Error())
+			return
+		}
+		r.printf(paint(decisionSkipped, msg("Skipping PR with comment: %s"))+"\n", pr.Title)
+		r.record(pr, decisionSkipped, "commented by user")
+	} else if answer.timedOut {
+		r.printf(paint(decisionSkipped, msg("Skipping PR: %s"))+"\n", pr.Title)
+		r.record(pr, decisionSkipped, "no answer")
+	} else {
+		r.printf(paint(decisionSkipped, msg("Skipping PR: %s"))+"\n", pr.Title)
+		r.record(pr, decisionSkipped, "declined by user")
+	}
+}
+
+func (r *runner) allPRsMerged(ctx context.Context, prs []*PullRequest) bool {
+	for _, pr := range prs {
+		detailsDone := r.timings.track("details")
+		prDetails, err := r.provider.GetPR(ctx, pr.Org, pr.Repo, pr.Number)
+		detailsDone()
+		if err != nil || !prDetails.Merged {
+			return false
+		}
+	}
+	return true
+}
+
+// undoLastApproval withdraws the approval of the PR approved last, unless it has been merged already.
+func (r *runner) undoLastApproval(ctx context.Context) {
+	pr := r.lastApproved
+	if pr == nil {
+		fmt.Println(msg("No approval to undo"))
+		return
+	}
+	current, err := r.provider.GetPR(ctx, pr.Org, pr.Repo, pr.Number)
+	if err != nil {
+		log.Printf("Error fetching PR details: %v", err)
+		return
+	}
+	if current.Merged {
+		fmt.Printf(msg("PR %s is already merged, its approval cannot be undone")+"\n", pr.URL)
+		return
+	}
+	if err := r.provider.Unapprove(ctx, pr); err != nil {
+		log.Printf("Error undoing approval: %v", err)
+		return
+	}
+	r.lastApproved = nil
+	fmt.Printf(msg("Withdrew approval of PR %s")+"\n", pr.URL)
+}
+
+// notifyReady shows a desktop notification the first time pr is ready to be confirmed, so that people waiting on
+// checks in another window can come back to the terminal.
+func (r *runner) notifyReady(pr *PullRequest) {
+	if r.notifiedReady[pr.URL] {
+		return
+	}
+	if r.notifiedReady == nil {
+		r.notifiedReady = map[string]bool{}
+	}
+	r.notifiedReady[pr.URL] = true
+	if err := desktopNotify("Renovate PR ready to merge", fmt.Sprintf("%s/%s: %s", pr.Org, pr.Repo, pr.Title)); err != nil {
+		log.Printf("Error showing desktop notification: %v", err)
+	}
+}
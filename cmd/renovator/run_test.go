@@ -0,0 +1,236 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// fakeProvider is a Provider serving PRs from memory and recording what renovator did to them.
+type fakeProvider struct {
+	prs map[int]*PullRequest
+	// checks are whether the checks of each PR passed, true when missing.
+	checks   map[int]bool
+	approved []int
+	merged   []int
+	// mergeErr fails merges when set.
+	mergeErr error
+}
+
+func newFakeProvider(prs ...*PullRequest) *fakeProvider {
+	p := &fakeProvider{prs: map[int]*PullRequest{}, checks: map[int]bool{}}
+	for _, pr := range prs {
+		p.prs[pr.Number] = pr
+	}
+	return p
+}
+
+func (p *fakeProvider) SearchUpdatePRs(ctx context.Context, query SearchQuery) ([]*PullRequest, error) {
+	var prs []*PullRequest
+	for _, pr := range p.prs {
+		found := *pr
+		found.HeadSHA = ""
+		prs = append(prs, &found)
+	}
+	return prs, nil
+}
+
+func (p *fakeProvider) GetPR(ctx context.Context, org, repo string, number int) (*PullRequest, error) {
+	pr, ok := p.prs[number]
+	if !ok {
+		return nil, fmt.Errorf("PR %d not found", number)
+	}
+	details := *pr
+	return &details, nil
+}
+
+func (p *fakeProvider) EvaluateChecks(ctx context.Context, pr *PullRequest) (bool, error) {
+	passed, ok := p.checks[pr.Number]
+	return passed || !ok, nil
+}
+
+func (p *fakeProvider) Approve(ctx context.Context, pr *PullRequest, comment string) error {
+	p.approved = append(p.approved, pr.Number)
+	return nil
+}
+
+func (p *fakeProvider) Merge(ctx context.Context, pr *PullRequest, method string) error {
+	if p.mergeErr != nil {
+		return p.mergeErr
+	}
+	p.merged = append(p.merged, pr.Number)
+	p.prs[pr.Number].Merged = true
+	return nil
+}
+
+func (p *fakeProvider) Unapprove(ctx context.Context, pr *PullRequest) error { return nil }
+
+func (p *fakeProvider) Close(ctx context.Context, pr *PullRequest) error { return nil }
+
+func (p *fakeProvider) Comment(ctx context.Context, pr *PullRequest, body string) error { return nil }
+
+func fakePR(number int, title string) *PullRequest {
+	return &PullRequest{
+		Org: "acme", Repo: "svc", Number: number, Title: title, Mergeable: true,
+		URL:       fmt.Sprintf("https://example.com/acme/svc/pull/%d", number),
+		HeadSHA:   fmt.Sprintf("sha%d", number),
+		CreatedAt: time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC),
+		Body:      "| Package | Change |\n|---|---|\n| lodash | `4.17.20` -> `4.17.21` |\n",
+	}
+}
+
+// newTestRunner returns an unattended runner of provider with the given policies.
+func newTestRunner(t *testing.T, provider Provider, policies ...policyConfig) *runner {
+	t.Helper()
+	compiled, err := compilePolicies(policies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verbosity = -1
+	t.Cleanup(func() { verbosity = 0 })
+	return &runner{
+		provider: provider,
+		opts:     options{yes: true},
+		report:   &report{},
+		target:   "fake acme",
+		settings: &settings{policies: compiled},
+	}
+}
+
+// onlyResult returns the result of the single PR processed by r.
+func onlyResult(t *testing.T, r *runner) prResult {
+	t.Helper()
+	if len(r.report.results) != 1 {
+		t.Fatalf("got %d results, want 1: %+v", len(r.report.results), r.report.results)
+	}
+	return r.report.results[0]
+}
+
+func TestProcessPRDecisions(t *testing.T) {
+	tests := []struct {
+		name     string
+		pr       func() *PullRequest
+		checks   bool
+		decision decision
+		reason   string
+	}{
+		{"ready", func() *PullRequest { return fakePR(1, "Update dependency lodash to v4.17.21") }, true,
+			decisionMerged, ""},
+		{"failing checks", func() *PullRequest { return fakePR(1, "Update dependency lodash to v4.17.21") }, false,
+			decisionSkipped, "checks not succeeded"},
+		{"draft", func() *PullRequest {
+			pr := fakePR(1, "Update dependency lodash to v4.17.21")
+			pr.Draft = true
+			return pr
+		}, true, decisionSkipped, "draft"},
+		{"not mergeable", func() *PullRequest {
+			pr := fakePR(1, "Update dependency lodash to v4.17.21")
+			pr.Mergeable = false
+			return pr
+		}, true, decisionSkipped, "not mergeable"},
+		{"do not merge", func() *PullRequest {
+			pr := fakePR(1, "Update dependency lodash to v4.17.21")
+			pr.Labels = []string{"do-not-merge/hold"}
+			return pr
+		}, true, decisionSkipped, "do not merge"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			provider := newFakeProvider(test.pr())
+			provider.checks[1] = test.checks
+			r := newTestRunner(t, provider)
+			r.processPR(context.Background(), fakePR(1, "Update dependency lodash to v4.17.21"))
+
+			result := onlyResult(t, r)
+			if result.Decision != test.decision || result.Reason != test.reason {
+				t.Errorf("got %s %q, want %s %q", result.Decision, result.Reason, test.decision, test.reason)
+			}
+			if merged := len(provider.merged) > 0; merged != (test.decision == decisionMerged) {
+				t.Errorf("merged %v, want a merge only when decided", provider.merged)
+			}
+		})
+	}
+}
+
+func TestProcessPRMergeFailure(t *testing.T) {
+	provider := newFakeProvider(fakePR(1, "Update dependency lodash to v4.17.21"))
+	provider.mergeErr = fmt.Errorf("merge refused")
+	r := newTestRunner(t, provider)
+	r.processPR(context.Background(), fakePR(1, "Update dependency lodash to v4.17.21"))
+
+	result := onlyResult(t, r)
+	if result.Decision != decisionFailed || result.Reason != "merge refused" {
+		t.Errorf("got %s %q, want a failed merge", result.Decision, result.Reason)
+	}
+	if len(provider.approved) != 1 {
+		t.Errorf("approved %v, want the PR approved before merging", provider.approved)
+	}
+}
+
+func TestProcessPRPolicies(t *testing.T) {
+	tests := []struct {
+		name     string
+		policies []policyConfig
+		decision decision
+		reason   string
+	}{
+		{"no policy", nil, decisionMerged, ""},
+		{"merge", []policyConfig{{When: `update.type == "patch"`, Action: policyMerge}}, decisionMerged, ""},
+		{"skip", []policyConfig{{When: `update.dependency == "lodash"`, Action: policySkip}},
+			decisionSkipped, "skipped by policy"},
+		{"prompt with -y", []policyConfig{{When: `update.type == "patch"`, Action: policyPrompt}},
+			decisionSkipped, "confirmation required by policy"},
+		{"first match", []policyConfig{
+			{When: `repo.name == "svc"`, Action: policySkip},
+			{When: `update.type == "patch"`, Action: policyMerge},
+		}, decisionSkipped, "skipped by policy"},
+		{"no match", []policyConfig{{When: `update.type == "major"`, Action: policySkip}}, decisionMerged, ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			provider := newFakeProvider(fakePR(1, "Update dependency lodash to v4.17.21"))
+			r := newTestRunner(t, provider, test.policies...)
+			r.processPR(context.Background(), fakePR(1, "Update dependency lodash to v4.17.21"))
+
+			result := onlyResult(t, r)
+			if result.Decision != test.decision || result.Reason != test.reason {
+				t.Errorf("got %s %q, want %s %q", result.Decision, result.Reason, test.decision, test.reason)
+			}
+		})
+	}
+}
+
+func TestApplyPlanChecksHead(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		head     string
+		decision decision
+	}{
+		{"unchanged", "sha1", decisionMerged},
+		{"moved", "sha2", decisionFailed},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			pr := fakePR(1, "Update dependency lodash to v4.17.21")
+			pr.HeadSHA = test.head
+			provider := newFakeProvider(pr)
+			r := newTestRunner(t, provider)
+			r.planned = []plannedPR{{Target: r.target, Org: "acme", Repo: "svc", Number: 1, Title: pr.Title, URL: pr.URL,
+				HeadSHA: "sha1"}}
+
+			prs, err := r.findPRs(context.Background(), SearchQuery{Org: "acme"})
+			if err != nil || len(prs) != 1 {
+				t.Fatalf("findPRs returned %v, %v", prs, err)
+			}
+			r.processPR(context.Background(), prs[0])
+
+			result := onlyResult(t, r)
+			if result.Decision != test.decision {
+				t.Errorf("got %s %q, want %s", result.Decision, result.Reason, test.decision)
+			}
+			if test.decision == decisionFailed && (result.Reason != "changed since planned" || len(provider.approved) > 0) {
+				t.Errorf("got %q and approvals %v, want the moved PR left alone", result.Reason, provider.approved)
+			}
+		})
+	}
+}
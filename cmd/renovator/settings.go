@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+)
+
+// settings are the parts of the configuration consulted per PR and per run. Daemon mode reloads them without
+// restarting.
+type settings struct {
+	freezes freezePeriods
+	// notifiers are sent the results of each run.
+	notifiers []notifier
+	// events is posted an event for each result when set.
+	events *webhookEmitter
+	// jira records merges when set.
+	jira *jiraRecorder
+	// plugins may deny approving or merging PRs.
+	plugins []pluginConfig
+	// policies decide whether PRs are merged, prompted for or skipped.
+	policies []policy
+	// opa decides on PRs before the policies when set.
+	opa *opaPolicy
+	// triage hands PRs that are not merged automatically over to people when set.
+	triage *triageConfig
+	// depsDev checks updates on deps.dev when set.
+	depsDev *depsDevChecker
+	// squashCommit renders the commit messages of squash merges when set.
+	squashCommit *commitTemplate
+	// planning attaches merged PRs to a milestone or project board when set.
+	planning *planningConfig
+	// escalation labels and assigns PRs failing their checks for long when set.
+	escalation *escalationConfig
+	// renovate asks Renovate to run again for repositories with conflicting or stale PRs when set.
+	renovate *renovateTrigger
+}
+
+// settingsFlags are the command line flags overriding settings of the config file.
+type settingsFlags struct {
+	freezeCalendar, teamsWebhook, discordWebhook, webhookURL, webhookSecretVariable string
+}
+
+// newSettings creates the settings of cfg, which is nil without a config file.
+func newSettings(cfg *config, flags settingsFlags) (*settings, error) {
+	if cfg == nil {
+		cfg = &config{}
+	}
+	s := &settings{freezes: cfg.Freezes, plugins: cfg.Plugins, triage: cfg.Triage, depsDev: newDepsDevChecker(cfg.DepsDev)}
+	var err error
+	if s.jira, err = newJiraRecorder(cfg.Jira); err != nil {
+		return nil, fmt.Errorf("configuring Jira: %w", err)
+	}
+	if s.policies, err = compilePolicies(cfg.Policies); err != nil {
+		return nil, fmt.Errorf("compiling policies: %w", err)
+	}
+	if s.opa, err = newOPAPolicy(cfg.OPA); err != nil {
+		return nil, fmt.Errorf("configuring OPA: %w", err)
+	}
+	if s.squashCommit, err = newCommitTemplate(cfg.SquashCommit); err != nil {
+		return nil, fmt.Errorf("configuring squashCommit: %w", err)
+	}
+	if s.renovate, err = newRenovateTrigger(cfg.RenovateTrigger); err != nil {
+		return nil, fmt.Errorf("configuring renovateTrigger: %w", err)
+	}
+	if cfg.Escalation != nil {
+		if err := cfg.Escalation.validate(); err != nil {
+			return nil, err
+		}
+		s.escalation = cfg.Escalation
+	}
+	if cfg.Planning != nil {
+		if err := cfg.Planning.validate(); err != nil {
+			return nil, err
+		}
+		s.planning = cfg.Planning
+	}
+
+	freezeCalendar := flags.freezeCalendar
+	if freezeCalendar == "" {
+		freezeCalendar = cfg.FreezeCalendar
+	}
+	if freezeCalendar != "" {
+		calendar, err := loadFreezeCalendar(freezeCalendar)
+		if err != nil {
+			return nil, fmt.Errorf("loading freeze calendar: %w", err)
+		}
+		s.freezes = append(s.freezes, calendar...)
+	}
+
+	notifications := cfg.Notifications
+	if flags.teamsWebhook != "" {
+		notifications.Teams = &teamsConfig{Webhook: flags.teamsWebhook}
+	}
+	if flags.discordWebhook != "" {
+		notifications.Discord = &discordConfig{Webhook: flags.discordWebhook}
+	}
+	if flags.webhookURL != "" {
+		notifications.Webhook = &webhookConfig{URL: flags.webhookURL, SecretVariable: flags.webhookSecretVariable}
+	}
+	s.notifiers = notifications.notifiers()
+	s.events = newWebhookEmitter(notifications.Webhook)
+	return s, nil
+}
+
+// configTargets returns the targets of cfg with empty fields taken from the flags.
+func configTargets(cfg *config, flagTarget targetConfig) []targetConfig {
+	if cfg == nil {
+		return []targetConfig{flagTarget}
+	}
+	configured := cfg.Targets
+	if len(configured) == 0 {
+		configured = []targetConfig{{}}
+	}
+	targets := make([]targetConfig, 0, len(configured))
+	for _, target := range configured {
+		targets = append(targets, cfg.resolveTarget(target, flagTarget))
+	}
+	return targets
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"golang.org/x/oauth2"
+	"gopkg.in/yaml.v3"
+)
+
+// the well-known location of the shared config of an org
+const (
+	sharedConfigRepo = ".renovator"
+	sharedConfigPath = "config.yaml"
+)
+
+// sharedConfigFields are the settings the shared config may set, by their YAML names. They decide what is merged
+// but neither run commands, read tokens nor choose where requests are sent, as anyone who can push to the .renovator
+// repository could otherwise run code, collect tokens or have every machine using it fetch a URL of their choosing.
+// For the same reason the freezeCalendar URL and the baseUrl of depsDev are only read from the local config.
+var sharedConfigFields = map[string]bool{
+	"freezes":      true,
+	"policies":     true,
+	"triage":       true,
+	"depsDev":      true,
+	"squashCommit": true,
+	"planning":     true,
+	"escalation":   true,
+}
+
+// fileProvider is implemented by providers that can read files from the default branch of a repository.
+type fileProvider interface {
+	GetFile(ctx context.Context, org, repo, path string) ([]byte, error)
+}
+
+// fetchSharedConfig reads the shared config from the .renovator repository of the org of target.
+func fetchSharedConfig(ctx context.Context, target targetConfig, ts oauth2.TokenSource, transport http.RoundTripper) (*config, error) {
+	if target.Org == "" {
+		return nil, fmt.Errorf("org is required to find the shared config")
+	}
+	provider, err := newProvider(target.Provider, target.BaseURL, ts, transport)
+	if err != nil {
+		return nil, err
+	}
+	files, ok := provider.(fileProvider)
+	if !ok {
+		return nil, fmt.Errorf("provider %s cannot read the shared config", target.Provider)
+	}
+	data, err := files.GetFile(ctx, target.Org, sharedConfigRepo, sharedConfigPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s/%s/%s: %w", target.Org, sharedConfigRepo, sharedConfigPath, err)
+	}
+	var cfg config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parsing %s/%s/%s: %w", target.Org, sharedConfigRepo, sharedConfigPath, err)
+	}
+	cfg, ignored := restrictSharedConfig(cfg)
+	if len(ignored) > 0 {
+		log.Printf("Ignoring %s in %s/%s/%s, which can only be set locally", strings.Join(ignored, ", "),
+			target.Org, sharedConfigRepo, sharedConfigPath)
+	}
+	return &cfg, nil
+}
+
+// restrictSharedConfig returns cfg with only the sharedConfigFields kept, and the names of the fields it clears.
+func restrictSharedConfig(cfg config) (config, []string) {
+	var ignored []string
+	value := reflect.ValueOf(&cfg).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		name, _, _ := strings.Cut(value.Type().Field(i).Tag.Get("yaml"), ",")
+		if sharedConfigFields[name] || value.Field(i).IsZero() {
+			continue
+		}
+		ignored = append(ignored, name)
+		value.Field(i).SetZero()
+	}
+	if cfg.DepsDev != nil && cfg.DepsDev.BaseURL != "" {
+		depsDev := *cfg.DepsDev
+		depsDev.BaseURL = ""
+		cfg.DepsDev = &depsDev
+		ignored = append(ignored, "depsDev.baseUrl")
+	}
+	return cfg, ignored
+}
+
+// mergeConfig returns local with the fields it leaves empty taken from shared. Maps are merged key by key, with the
+// entries of local taking precedence.
+func mergeConfig(shared, local config) config {
+	merged := local
+	mergedValue := reflect.ValueOf(&merged).Elem()
+	sharedValue := reflect.ValueOf(shared)
+	for i := 0; i < mergedValue.NumField(); i++ {
+		field, sharedField := mergedValue.Field(i), sharedValue.Field(i)
+		switch {
+		case sharedField.IsZero():
+		case field.IsZero():
+			field.Set(sharedField)
+		case field.Kind() == reflect.Map:
+			entries := reflect.MakeMap(field.Type())
+			for _, m := range []reflect.Value{sharedField, field} {
+				iter := m.MapRange()
+				for iter.Next() {
+					entries.SetMapIndex(iter.Key(), iter.Value())
+				}
+			}
+			field.Set(entries)
+		}
+	}
+	return merged
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func parseTestConfig(t *testing.T, doc string) config {
+	t.Helper()
+	var cfg config
+	if err := yaml.Unmarshal([]byte(doc), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	return cfg
+}
+
+func TestRestrictSharedConfig(t *testing.T) {
+	cfg := parseTestConfig(t, `
+targets:
+  - org: acme
+credentials:
+  acme: {tokenVariable: ACME_TOKEN}
+freezes:
+  - {start: 2026-12-20, end: 2027-01-03}
+freezeCalendar: https://calendar.example.com/freezes.ics
+plugins:
+  - command: [./approve.sh]
+policies:
+  - {when: 'update.type == "major"', action: skip}
+depsDev:
+  baseUrl: https://deps.example.com
+  minScorecard: 5
+`)
+	restricted, ignored := restrictSharedConfig(cfg)
+
+	if want := []string{"targets", "credentials", "freezeCalendar", "plugins", "depsDev.baseUrl"}; !reflect.DeepEqual(ignored, want) {
+		t.Errorf("ignored %v, want %v", ignored, want)
+	}
+	want := config{
+		Freezes:  cfg.Freezes,
+		Policies: cfg.Policies,
+		DepsDev:  &depsDevConfig{MinScorecard: 5},
+	}
+	if !reflect.DeepEqual(restricted, want) {
+		t.Errorf("got %+v, want %+v", restricted, want)
+	}
+	if cfg.DepsDev.BaseURL == "" {
+		t.Error("restricting cleared the baseUrl of the original config")
+	}
+}
+
+func TestMergeConfig(t *testing.T) {
+	shared := parseTestConfig(t, `
+credentials:
+  acme: {tokenVariable: SHARED_TOKEN}
+  initech: {tokenVariable: INITECH_TOKEN}
+policies:
+  - {when: 'update.type == "major"', action: skip}
+freezes:
+  - {start: 2026-12-20, end: 2027-01-03}
+triage:
+  comment: true
+`)
+	local := parseTestConfig(t, `
+credentials:
+  acme: {tokenVariable: ACME_TOKEN}
+policies:
+  - {when: 'update.type == "patch"', action: merge}
+`)
+	merged := mergeConfig(shared, local)
+
+	wantCredentials := map[string]tokenConfig{
+		"acme":    {TokenVariable: "ACME_TOKEN"},
+		"initech": {TokenVariable: "INITECH_TOKEN"},
+	}
+	if !reflect.DeepEqual(merged.Credentials, wantCredentials) {
+		t.Errorf("credentials %+v, want %+v", merged.Credentials, wantCredentials)
+	}
+	if !reflect.DeepEqual(merged.Policies, local.Policies) {
+		t.Errorf("policies %+v, want the local ones", merged.Policies)
+	}
+	if !reflect.DeepEqual(merged.Freezes, shared.Freezes) {
+		t.Errorf("freezes %+v, want the shared ones", merged.Freezes)
+	}
+	if merged.Triage != shared.Triage {
+		t.Errorf("triage %+v, want the shared one", merged.Triage)
+	}
+	if len(local.Credentials) != 1 {
+		t.Errorf("merging changed the local credentials to %+v", local.Credentials)
+	}
+}
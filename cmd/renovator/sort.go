@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// sortKeys are the values accepted by -sort.
+var sortKeys = []string{"repo", "dependency", "age", "priority", "status"}
+
+// priorityGroups are the groups of -sort priority in the order they are processed.
+var priorityGroups = []string{"security", "patch", "minor", "major", "other"}
+
+// sortGroup returns the group pr is listed under when sorting by key, or "" when the key does not group.
+func sortGroup(pr *PullRequest, key string) string {
+	switch key {
+	case "repo":
+		return pr.Org + "/" + pr.Repo
+	case "dependency":
+		if dependency := parseUpdate(pr).Dependency; dependency != "" {
+			return dependency
+		}
+		return pr.Title
+	case "priority":
+		if strings.Contains(strings.ToUpper(pr.Title), "[SECURITY]") {
+			return "security"
+		}
+		if kind := parseUpdate(pr).kind(); kind != "" {
+			return kind
+		}
+		return "other"
+	}
+	return ""
+}
+
+// prLess orders PRs by repo, dependency or age, oldest first, or by priority, oldest first within each priority.
+func prLess(a, b *PullRequest, key string) bool {
+	if key == "age" {
+		return a.CreatedAt.Before(b.CreatedAt)
+	}
+	if key == "priority" {
+		pa, pb := slices.Index(priorityGroups, sortGroup(a, key)), slices.Index(priorityGroups, sortGroup(b, key))
+		if pa != pb {
+			return pa < pb
+		}
+		return a.CreatedAt.Before(b.CreatedAt)
+	}
+	ga, gb := sortGroup(a, key), sortGroup(b, key)
+	if ga != gb {
+		return ga < gb
+	}
+	return a.Number < b.Number
+}
+
+// sortPRs orders prs for processing. The status is only known once a PR has been evaluated, so sorting by
+// status leaves prs in search order.
+func sortPRs(prs []*PullRequest, key string) {
+	if key == "status" {
+		return
+	}
+	sort.SliceStable(prs, func(i, j int) bool {
+		return prLess(prs[i], prs[j], key)
+	})
+}
+
+// printSorted lists the results of target grouped and sorted by key.
+func (r *report) printSorted(target, key string) {
+	r.mu.Lock()
+	var results []prResult
+	for _, result := range r.results {
+		if result.Target == target {
+			results = append(results, result)
+		}
+	}
+	r.mu.Unlock()
+
+	statusOrder := map[decision]int{decisionMerged: 0, decisionSkipped: 1, decisionFailed: 2}
+	sort.SliceStable(results, func(i, j int) bool {
+		if key == "status" {
+			return statusOrder[results[i].Decision] < statusOrder[results[j].Decision]
+		}
+		return prLess(results[i].PR, results[j].PR, key)
+	})
+
+	infof("\n"+msg("Results by %s:")+"\n", key)
+	group := "\x00"
+	for _, result := range results {
+		next := sortGroup(result.PR, key)
+		if key == "status" {
+			next = string(result.Decision)
+		}
+		if next != group && next != "" {
+			infof("%s\n", next)
+		}
+		group = next
+
+		line := fmt.Sprintf("%-8s %s/%s#%d %s", result.Decision, result.PR.Org, result.PR.Repo, result.PR.Number, result.PR.Title)
+		if key == "age" && !result.PR.CreatedAt.IsZero() {
+			line += fmt.Sprintf(", open %d days", int(time.Since(result.PR.CreatedAt).Hours()/24))
+		}
+		if result.Reason != "" {
+			line += " (" + result.Reason + ")"
+		}
+		infof("  %s\n", paint(result.Decision, line))
+	}
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// stateRecord is the result of a PR kept in the state file.
+type stateRecord struct {
+	// Run is when the run the record was added in started.
+	Run       time.Time `json:"run"`
+	Time      time.Time `json:"time"`
+	Target    string    `json:"target"`
+	Org       string    `json:"org"`
+	Repo      string    `json:"repo"`
+	Number    int       `json:"number"`
+	Title     string    `json:"title"`
+	URL       string    `json:"url"`
+	Update    update    `json:"update"`
+	CreatedAt time.Time `json:"createdAt"`
+	HeadSHA   string    `json:"headSha,omitempty"`
+	Decision  decision  `json:"decision"`
+	Reason    string    `json:"reason,omitempty"`
+	// ReasonCode classifies Reason, see reasonCode.
+	ReasonCode reasonCode `json:"reasonCode,omitempty"`
+	// Unattended is set for the results of runs with -y, which skip the PRs they would otherwise have asked about.
+	Unattended bool `json:"unattended,omitempty"`
+	// Triaged is set when the PR was handed over to people at HeadSHA, so that later runs do not triage it again.
+	Triaged bool `json:"triaged,omitempty"`
+}
+
+// stateStore keeps the results of all runs in a JSON lines file, so that later runs and commands such as campaign
+// can look back at them. A nil store keeps nothing.
+type stateStore struct {
+	path string
+	mu   sync.Mutex
+	run  time.Time
+}
+
+// newStateStore returns a store kept at path, or nil when path is empty.
+func newStateStore(path string) *stateStore {
+	if path == "" {
+		return nil
+	}
+	return &stateStore{path: path}
+}
+
+// defaultStatePath returns the state file in the user config directory, or "" when there is none.
+func defaultStatePath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "renovator", "state.jsonl")
+}
+
+// startRun marks the records added from now on as added in a run started at start.
+func (s *stateStore) startRun(start time.Time) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.run = start.UTC()
+}
+
+// add appends the result of a run, unattended with -y or not, to the state file, logging failures as the run goes on
+// without it.
+func (s *stateStore) add(result prResult, unattended bool) {
+	if s == nil {
+		return
+	}
+	record := stateRecord{
+		Time: time.Now().UTC(), Target: result.Target,
+		Org: result.PR.Org, Repo: result.PR.Repo, Number: result.PR.Number, Title: result.PR.Title, URL: result.PR.URL,
+		Update: parseUpdate(result.PR), CreatedAt: result.PR.CreatedAt, HeadSHA: result.PR.HeadSHA,
+		Decision: result.Decision, Reason: result.Reason, ReasonCode: result.Code, Unattended: unattended,
+		Triaged: result.Triaged,
+	}
+	if err := s.append(record); err != nil {
+		log.Printf("Error writing state to %s: %v", s.path, err)
+	}
+}
+
+func (s *stateStore) append(record stateRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record.Run = s.run
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if err := json.NewEncoder(file).Encode(record); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// records returns all records of the state file in the order they were added.
+func (s *stateStore) records() ([]stateRecord, error) {
+	if s == nil {
+		return nil, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file, err := os.Open(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var records []stateRecord
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		var record stateRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("%s line %d: %w", s.path, line, err)
+		}
+		records = append(records, record)
+	}
+	return records, scanner.Err()
+}
+
+// unchangedReasons are the skip reasons that hold as long as the head of a PR does not change. Those mapped to true
+// only skip PRs because nobody was there to ask with -y, so they only hold for later runs with -y too.
+var unchangedReasons = map[string]bool{
+	"skipped by policy":               false,
+	"declined by user":                false,
+	"commented by user":               false,
+	"repo opted out":                  true,
+	"confirmation required by policy": true,
+	"license changed":                 true,
+	"breaking change":                 true,
+}
+
+// lastDecisions returns the last record of each PR in the state file, keyed by URL.
+func (s *stateStore) lastDecisions() (map[string]stateRecord, error) {
+	records, err := s.records()
+	if err != nil {
+		return nil, err
+	}
+	last := map[string]stateRecord{}
+	for _, record := range records {
+		last[record.URL] = record
+	}
+	return last, nil
+}
+
+// unchangedSkip returns the record of the previous run that skipped pr for a reason that still holds, as the head
+// of pr has not changed since and the run is as unattended as the one that skipped it.
+func (r *runner) unchangedSkip(pr *PullRequest) (stateRecord, bool) {
+	record, ok := r.previous[pr.URL]
+	if !r.opts.skipUnchanged || !ok || record.HeadSHA == "" || record.HeadSHA != pr.HeadSHA || record.Decision != decisionSkipped {
+		return stateRecord{}, false
+	}
+	if strings.HasPrefix(record.Reason, "denied by plugin ") {
+		return record, true
+	}
+	unattendedOnly, ok := unchangedReasons[record.Reason]
+	return record, ok && (!unattendedOnly || record.Unattended && r.opts.yes)
+}
+
+// triagedBefore reports whether a previous run triaged pr at its current head.
+func (r *runner) triagedBefore(pr *PullRequest) bool {
+	record, ok := r.previous[pr.URL]
+	return ok && record.Triaged && record.HeadSHA != "" && record.HeadSHA == pr.HeadSHA
+}
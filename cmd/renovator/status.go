@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// reviewStatusProvider is implemented by providers that can report the approvals of a PR and what the protection
+// of its base branch requires.
+type reviewStatusProvider interface {
+	ReviewStatus(ctx context.Context, pr *PullRequest) (reviewStatus, error)
+}
+
+// reviewStatus are the approvals of a PR.
+type reviewStatus struct {
+	Approvals int
+	// Protection is nil when the protection of the base branch cannot be read.
+	Protection *branchProtection
+}
+
+// branchProtection is what the base branch of a PR requires before merging.
+type branchProtection struct {
+	RequiredApprovals int      `json:"requiredApprovals"`
+	RequiredChecks    []string `json:"requiredChecks,omitempty"`
+	// RequiresConversationResolution is set when all review threads have to be resolved.
+	RequiresConversationResolution bool `json:"requiresConversationResolution,omitempty"`
+}
+
+// prStatus is the state of a PR reported by renovator status.
+type prStatus struct {
+	Target     string            `json:"target"`
+	Org        string            `json:"org"`
+	Repo       string            `json:"repo"`
+	Number     int               `json:"number"`
+	Title      string            `json:"title"`
+	URL        string            `json:"url"`
+	Author     string            `json:"author"`
+	HeadSHA    string            `json:"headSha,omitempty"`
+	Update     update            `json:"update"`
+	CreatedAt  time.Time         `json:"createdAt"`
+	Mergeable  bool              `json:"mergeable"`
+	Checks     string            `json:"checks"`
+	Approvals  *int              `json:"approvals,omitempty"`
+	Protection *branchProtection `json:"protection,omitempty"`
+	Error      string            `json:"error,omitempty"`
+}
+
+// ready reports whether the PR can be merged as far as renovator can tell, counting the approval renovator gives.
+func (s prStatus) ready() bool {
+	if !s.Mergeable || s.Checks != "passing" || s.Error != "" {
+		return false
+	}
+	return s.Approvals == nil || s.Protection == nil || *s.Approvals+1 >= s.Protection.RequiredApprovals
+}
+
+// status returns the state of the matching PRs without approving or merging anything.
+func (r *runner) status(ctx context.Context) ([]prStatus, error) {
+	query := SearchQuery{Org: r.opts.org, User: r.opts.user, Repo: r.opts.repo, Author: r.opts.author}
+	prs, err := r.searchPRs(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("error searching PRs: %w", err)
+	}
+	if prs, err = r.filterPaths(ctx, prs); err != nil {
+		return nil, err
+	}
+	if prs, err = r.filterRepos(ctx, prs); err != nil {
+		return nil, err
+	}
+	sortPRs(prs, r.opts.sortBy)
+
+	var statuses []prStatus
+	for _, pr := range prs {
+		if r.opts.dependency != "" && !r.matchesDependency(ctx, pr) {
+			continue
+		}
+		statuses = append(statuses, r.prStatus(ctx, pr))
+	}
+	return statuses, nil
+}
+
+func (r *runner) prStatus(ctx context.Context, pr *PullRequest) prStatus {
+	s := prStatus{
+		Target: r.target, Org: pr.Org, Repo: pr.Repo, Number: pr.Number, Title: pr.Title, URL: pr.URL,
+		Author: pr.Author, Update: parseUpdate(pr), CreatedAt: pr.CreatedAt,
+	}
+	prDetails, err := r.provider.GetPR(ctx, pr.Org, pr.Repo, pr.Number)
+	if err != nil {
+		s.Error = err.Error()
+		return s
+	}
+	s.Mergeable, s.HeadSHA = prDetails.Mergeable, prDetails.HeadSHA
+	passed, err := r.provider.EvaluateChecks(ctx, prDetails)
+	if err != nil {
+		s.Error = err.Error()
+		return s
+	}
+	s.Checks = "failing"
+	if passed {
+		s.Checks = "passing"
+	}
+	if provider, ok := r.provider.(reviewStatusProvider); ok {
+		reviews, err := provider.ReviewStatus(ctx, prDetails)
+		if err != nil {
+			s.Error = err.Error()
+			return s
+		}
+		s.Approvals, s.Protection = &reviews.Approvals, reviews.Protection
+	}
+	return s
+}
+
+// writeStatus writes the statuses as a table followed by the number of PRs ready to merge, or as JSON.
+func writeStatus(w io.Writer, statuses []prStatus, output string) error {
+	if output == "json" {
+		if statuses == nil {
+			statuses = []prStatus{}
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(statuses)
+	}
+
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, msg("PR\tUPDATE\tAGE\tMERGEABLE\tCHECKS\tAPPROVALS\tREQUIRED CHECKS"))
+	ready := 0
+	for _, s := range statuses {
+		if s.ready() {
+			ready++
+		}
+		change := s.Title
+		if s.Update.Dependency != "" && s.Update.From != "" {
+			change = fmt.Sprintf("%s %s -> %s", s.Update.Dependency, s.Update.From, s.Update.To)
+		}
+		checks, mergeable := s.Checks, "no"
+		if s.Mergeable {
+			mergeable = "yes"
+		}
+		if s.Error != "" {
+			checks, mergeable = "error: "+s.Error, "?"
+		}
+		approvals, requiredChecks := "?", ""
+		if s.Approvals != nil {
+			approvals = strconv.Itoa(*s.Approvals)
+		}
+		if s.Protection != nil {
+			approvals += "/" + strconv.Itoa(s.Protection.RequiredApprovals)
+			requiredChecks = strings.Join(s.Protection.RequiredChecks, ", ")
+		}
+		fmt.Fprintf(table, "%s/%s#%d\t%s\t%s\t%s\t%s\t%s\t%s\n", s.Org, s.Repo, s.Number, change,
+			formatAge(time.Since(s.CreatedAt)), mergeable, checks, approvals, requiredChecks)
+	}
+	if err := table.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "\n"+msg("%d of %d PRs ready to merge")+"\n", ready, len(statuses))
+	return err
+}
+
+// formatAge formats the age of a PR in days, or hours for PRs younger than a day.
+func formatAge(age time.Duration) string {
+	if age < 24*time.Hour {
+		return fmt.Sprintf("%dh", int(age.Hours()))
+	}
+	return fmt.Sprintf("%dd", int(age.Hours()/24))
+}
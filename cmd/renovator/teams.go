@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// teamsConfig configures notifications to a Microsoft Teams channel.
+type teamsConfig struct {
+	// Webhook is the URL of an incoming webhook or a Workflows "post to a channel when a webhook request is
+	// received" trigger.
+	Webhook string `yaml:"webhook"`
+}
+
+// teamsNotifier posts the results of a run to Microsoft Teams as an adaptive card.
+type teamsNotifier struct {
+	webhook string
+}
+
+func (n *teamsNotifier) Notify(ctx context.Context, summary runSummary) error {
+	body := []map[string]any{{
+		"type":   "TextBlock",
+		"text":   fmt.Sprintf("Renovator run finished in %s", summary.Elapsed.Round(time.Millisecond)),
+		"weight": "Bolder",
+		"size":   "Medium",
+	}}
+	sections := []struct {
+		title   string
+		color   string
+		results []prResult
+	}{
+		{"Merged", "Good", summary.Merged},
+		{"Skipped", "Warning", summary.Skipped},
+		{"Failed", "Attention", summary.Failed},
+	}
+	for _, section := range sections {
+		if len(section.results) == 0 {
+			continue
+		}
+		body = append(body, map[string]any{
+			"type":      "TextBlock",
+			"text":      fmt.Sprintf("%s (%d)", section.title, len(section.results)),
+			"weight":    "Bolder",
+			"color":     section.color,
+			"separator": true,
+		})
+		for _, result := range section.results {
+			text := fmt.Sprintf("- [%s/%s#%d](%s) %s", result.PR.Org, result.PR.Repo, result.PR.Number, result.PR.URL, result.PR.Title)
+			if result.Reason != "" {
+				text += " (" + result.Reason + ")"
+			}
+			body = append(body, map[string]any{"type": "TextBlock", "text": text, "wrap": true, "spacing": "None"})
+		}
+	}
+
+	payload := map[string]any{
+		"type": "message",
+		"attachments": []map[string]any{{
+			"contentType": "application/vnd.microsoft.card.adaptive",
+			"content": map[string]any{
+				"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+				"type":    "AdaptiveCard",
+				"version": "1.4",
+				"body":    body,
+			},
+		}},
+	}
+	if err := postJSON(ctx, n.webhook, payload); err != nil {
+		return fmt.Errorf("posting to Teams: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"log"
+	"slices"
+	"strings"
+)
+
+// reviewThread is an unresolved review thread of a PR.
+type reviewThread struct {
+	ID string
+	// Author started the thread, Bot is set when it is an app or bot account.
+	Author string
+	Bot    bool
+}
+
+// reviewThreadsProvider is implemented by providers that can list and resolve the review threads of a PR.
+type reviewThreadsProvider interface {
+	// UnresolvedThreads returns the review threads of pr that are not resolved.
+	UnresolvedThreads(ctx context.Context, pr *PullRequest) ([]reviewThread, error)
+	ResolveThread(ctx context.Context, pr *PullRequest, id string) error
+}
+
+// checkThreads reports the unresolved review threads of pr, which some branch protections block merging on, after
+// resolving those started by bots with -resolve-bot-threads, once per PR while renovator runs. It returns how many
+// remain unresolved, or -1 when the provider cannot tell.
+func (r *runner) checkThreads(ctx context.Context, pr *PullRequest) int {
+	provider, ok := r.provider.(reviewThreadsProvider)
+	if !ok {
+		return -1
+	}
+	if unresolved, ok := r.unresolvedThreads[pr.URL]; ok {
+		return unresolved
+	}
+	threads, err := provider.UnresolvedThreads(ctx, pr)
+	if err != nil {
+		verbosef("Error reading the review threads of %s: %v\n", pr.URL, err)
+		return -1
+	}
+
+	var authors []string
+	unresolved := 0
+	for _, thread := range threads {
+		if thread.Bot && r.opts.resolveBotThreads {
+			if err := provider.ResolveThread(ctx, pr, thread.ID); err != nil {
+				log.Printf("Error resolving review thread of %s by %s: %v", pr.URL, thread.Author, err)
+			} else {
+				verbosef("Resolved review thread by %s\n", thread.Author)
+				continue
+			}
+		}
+		unresolved++
+		authors = append(authors, thread.Author)
+	}
+	if unresolved > 0 {
+		slices.Sort(authors)
+		r.printf(paint(decisionSkipped, msg("PR %s has %d unresolved review threads, by %s"))+"\n", pr.Title, unresolved,
+			strings.Join(slices.Compact(authors), ", "))
+	}
+	if r.unresolvedThreads == nil {
+		r.unresolvedThreads = map[string]int{}
+	}
+	r.unresolvedThreads[pr.URL] = unresolved
+	return unresolved
+}
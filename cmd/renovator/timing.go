@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// phases are the phases of a run timings are reported for, in the order they are reported.
+var phases = []string{"search", "details", "checks", "approve", "merge"}
+
+// phaseTimings accumulates the time spent and the number of calls made in each phase of a run.
+type phaseTimings struct {
+	mu    sync.Mutex
+	total map[string]time.Duration
+	calls map[string]int
+}
+
+// track starts timing a call in phase, returning a function to call when it completes.
+func (t *phaseTimings) track(phase string) func() {
+	start := time.Now()
+	return func() {
+		elapsed := time.Since(start)
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		if t.total == nil {
+			t.total, t.calls = map[string]time.Duration{}, map[string]int{}
+		}
+		t.total[phase] += elapsed
+		t.calls[phase]++
+	}
+}
+
+// print outputs the time spent per phase with -v and starts over for the next run.
+func (t *phaseTimings) print(label string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	defer func() { t.total, t.calls = nil, nil }()
+	var parts []string
+	for _, phase := range phases {
+		if calls := t.calls[phase]; calls > 0 {
+			parts = append(parts, fmt.Sprintf("%s %s (%d calls)", phase, t.total[phase].Round(time.Millisecond), calls))
+		}
+	}
+	if len(parts) > 0 {
+		verbosef("Time spent (%s): %s\n", label, strings.Join(parts, ", "))
+	}
+}
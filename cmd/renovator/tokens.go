@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+
+	"golang.org/x/oauth2"
+)
+
+// resolveTokenSource returns a token source for the target. Tokens read from Vault are re-read when they expire,
+// all other sources are resolved once. Several tokens separated by commas or whitespace are rotated through as
+// their rate limits are exhausted.
+func resolveTokenSource(token string, target targetConfig, allowReadableFile bool) (oauth2.TokenSource, error) {
+	if token == "" && target.TokenVault != "" {
+		field := target.TokenVaultField
+		if field == "" {
+			field = "token"
+		}
+		source, err := newVaultTokenSource(target.TokenVault, field)
+		if err != nil {
+			return nil, err
+		}
+		// read once up front so that misconfiguration fails the run before anything is processed
+		if _, err := source.Token(); err != nil {
+			return nil, err
+		}
+		return source, nil
+	}
+
+	token, err := resolveToken(token, target, allowReadableFile)
+	if err != nil {
+		return nil, err
+	}
+	if tokens := splitTokens(token); len(tokens) > 1 {
+		return newRotatingTokenSource(tokens), nil
+	}
+	return oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token}), nil
+}
+
+// resolveToken returns token when given, otherwise the token from the keyring account, AWS secret or parameter,
+// file or environment variable configured for the target. GitHub targets without any of these fall back to the
+// GITHUB_TOKEN and GH_TOKEN environment variables.
+func resolveToken(token string, target targetConfig, allowReadableFile bool) (string, error) {
+	if token != "" {
+		return token, nil
+	}
+	switch {
+	case target.TokenKeyring != "":
+		return keyringToken(target.TokenKeyring)
+	case target.TokenAWSSecret != "":
+		return awsSecretToken(context.Background(), target.TokenAWSSecret)
+	case target.TokenAWSParameter != "":
+		return awsParameterToken(context.Background(), target.TokenAWSParameter)
+	case target.TokenFile != "":
+		return fileToken(target.TokenFile, allowReadableFile)
+	case target.TokenVariable != "":
+		var tokens []string
+		for _, variable := range strings.Split(target.TokenVariable, ",") {
+			if value := os.Getenv(strings.TrimSpace(variable)); value != "" {
+				tokens = append(tokens, value)
+			}
+		}
+		if len(tokens) == 0 {
+			return "", fmt.Errorf("token is required")
+		}
+		return strings.Join(tokens, ","), nil
+	case target.Provider == "github":
+		// the conventional variables of GitHub Actions and the gh CLI
+		for _, variable := range []string{"GITHUB_TOKEN", "GH_TOKEN"} {
+			if value := os.Getenv(variable); value != "" {
+				return value, nil
+			}
+		}
+		fallthrough
+	default:
+		return "", fmt.Errorf("either token, token-variable, token-file, token-keyring, token-vault, token-aws-secret or token-aws-parameter must be provided")
+	}
+}
+
+// fileToken reads a token from path, trimming surrounding whitespace. Files readable by all users are refused
+// unless allowReadable is set.
+func fileToken(path string, allowReadable bool) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0o004 != 0 && !allowReadable {
+		return "", fmt.Errorf("token file %s is world-readable (%s), restrict its permissions or use -allow-world-readable-token-file", path, info.Mode().Perm())
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", fmt.Errorf("token file %s is empty", path)
+	}
+	return token, nil
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// triageConfig hands PRs that are not merged automatically over to people, by requesting their review and
+// commenting on the PR why it was not merged.
+type triageConfig struct {
+	// Reviewers are the users and Teams the team slugs requested to review the PR.
+	Reviewers []string `yaml:"reviewers"`
+	Teams     []string `yaml:"teams"`
+	// Comment enables a comment summarizing the update and why it was not merged.
+	Comment bool `yaml:"comment"`
+	// Mention are pinged in the comment, e.g. @acme/platform.
+	Mention []string `yaml:"mention"`
+}
+
+// triageReasons are the skip reasons of PRs that need a human to decide on them.
+var triageReasons = map[string]bool{
+	"checks not succeeded":            true,
+	"skipped by policy":               true,
+	"confirmation required by policy": true,
+	"license changed":                 true,
+	"breaking change":                 true,
+	"more approvals required":         true,
+}
+
+// reviewRequester is implemented by providers that can request reviews from users and teams.
+type reviewRequester interface {
+	RequestReview(ctx context.Context, pr *PullRequest, reviewers, teams []string) error
+}
+
+// triage requests reviews for and comments on a PR skipped for reason, once per head of the PR as kept in the
+// state, or once while renovator runs without one.
+func (r *runner) triage(ctx context.Context, pr *PullRequest, reason string) {
+	t := r.settings.triage
+	if t == nil || !triageReasons[reason] || r.triaged[pr.URL] || r.triagedBefore(pr) {
+		return
+	}
+	if r.triaged == nil {
+		r.triaged = map[string]bool{}
+	}
+	r.triaged[pr.URL] = true
+
+	if len(t.Reviewers) > 0 || len(t.Teams) > 0 {
+		if requester, ok := r.provider.(reviewRequester); !ok {
+			log.Printf("Requesting reviews is not supported by the provider of %s", r.target)
+		} else if err := requester.RequestReview(ctx, pr, t.Reviewers, t.Teams); err != nil {
+			log.Printf("Error requesting review of %s: %v", pr.URL, err)
+		} else {
+			verbosef("Requested review of %s\n", pr.URL)
+		}
+	}
+	if t.Comment {
+		if err := r.provider.Comment(ctx, pr, triageComment(pr, reason, t.Mention)); err != nil {
+			log.Printf("Error commenting on %s: %v", pr.URL, err)
+		}
+	}
+}
+
+// triageComment summarizes the update of pr and why it was not merged.
+func triageComment(pr *PullRequest, reason string, mention []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Renovator did not merge this PR: %s.\n", reason)
+	if u := parseUpdate(pr); u.Dependency != "" && u.From != "" {
+		fmt.Fprintf(&b, "\n%s is updated from %s to %s", u.Dependency, u.From, u.To)
+		if kind := u.kind(); kind != "" {
+			fmt.Fprintf(&b, " (%s)", kind)
+		}
+		b.WriteString(".\n")
+	}
+	if len(mention) > 0 {
+		fmt.Fprintf(&b, "\n%s, please review.\n", strings.Join(mention, " "))
+	}
+	return b.String()
+}
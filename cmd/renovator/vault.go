@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// vaultRefreshInterval is how often a secret without a lease is re-read.
+const vaultRefreshInterval = time.Hour
+
+const kubernetesServiceAccountToken = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+// vaultTokenSource reads a token from a HashiCorp Vault KV secret. It authenticates with VAULT_TOKEN or, when
+// VAULT_K8S_ROLE is set, with the Kubernetes service account of the pod. VAULT_ADDR, VAULT_NAMESPACE and
+// VAULT_K8S_MOUNT (default kubernetes) are honoured as by the Vault CLI.
+//
+// The secret is re-read when its lease expires, or hourly for secrets without a lease such as KV v2 entries.
+type vaultTokenSource struct {
+	api   *restClient
+	path  string
+	field string
+
+	mu          sync.Mutex
+	vaultToken  string
+	tokenExpiry time.Time
+}
+
+// newVaultTokenSource creates a token source reading field of the secret at path, e.g. secret/data/renovator
+// for a KV v2 secret or secret/renovator for KV v1.
+func newVaultTokenSource(path, field string) (oauth2.TokenSource, error) {
+	addr := os.Getenv("VAULT_ADDR")
+	if addr == "" {
+		return nil, fmt.Errorf("VAULT_ADDR must be set to read the token from Vault")
+	}
+	api := newRESTClient(strings.TrimSuffix(addr, "/")+"/v1", http.DefaultClient)
+	if namespace := os.Getenv("VAULT_NAMESPACE"); namespace != "" {
+		api.header.Set("X-Vault-Namespace", namespace)
+	}
+	source := &vaultTokenSource{api: api, path: strings.Trim(path, "/"), field: field}
+	return oauth2.ReuseTokenSource(nil, source), nil
+}
+
+func (s *vaultTokenSource) Token() (*oauth2.Token, error) {
+	ctx := context.Background()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.login(ctx); err != nil {
+		return nil, fmt.Errorf("authenticating to Vault: %w", err)
+	}
+
+	var secret struct {
+		LeaseDuration int                    `json:"lease_duration"`
+		Data          map[string]interface{} `json:"data"`
+	}
+	s.api.header.Set("X-Vault-Token", s.vaultToken)
+	if err := s.api.do(ctx, http.MethodGet, "/"+s.path, nil, &secret); err != nil {
+		return nil, fmt.Errorf("reading Vault secret %s: %w", s.path, err)
+	}
+
+	data := secret.Data
+	// KV v2 nests the secret data under data.data
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		data = nested
+	}
+	value, ok := data[s.field].(string)
+	if !ok || value == "" {
+		return nil, fmt.Errorf("vault secret %s has no field %q", s.path, s.field)
+	}
+
+	lease := vaultRefreshInterval
+	if secret.LeaseDuration > 0 {
+		lease = time.Duration(secret.LeaseDuration) * time.Second
+	}
+	return &oauth2.Token{AccessToken: value, Expiry: time.Now().Add(lease)}, nil
+}
+
+// login obtains a Vault token, reusing the current one until it expires.
+func (s *vaultTokenSource) login(ctx context.Context) error {
+	if token := os.Getenv("VAULT_TOKEN"); token != "" {
+		s.vaultToken = token
+		return nil
+	}
+	if s.vaultToken != "" && time.Now().Before(s.tokenExpiry) {
+		return nil
+	}
+
+	role := os.Getenv("VAULT_K8S_ROLE")
+	if role == "" {
+		return fmt.Errorf("either VAULT_TOKEN or VAULT_K8S_ROLE must be set")
+	}
+	mount := os.Getenv("VAULT_K8S_MOUNT")
+	if mount == "" {
+		mount = "kubernetes"
+	}
+	jwt, err := os.ReadFile(kubernetesServiceAccountToken)
+	if err != nil {
+		return err
+	}
+
+	var login struct {
+		Auth struct {
+			ClientToken   string `json:"client_token"`
+			LeaseDuration int    `json:"lease_duration"`
+		} `json:"auth"`
+	}
+	s.api.header.Del("X-Vault-Token")
+	body := map[string]string{"role": role, "jwt": strings.TrimSpace(string(jwt))}
+	if err := s.api.do(ctx, http.MethodPost, "/auth/"+mount+"/login", body, &login); err != nil {
+		return err
+	}
+	s.vaultToken = login.Auth.ClientToken
+	// renew slightly before the Vault token actually expires
+	s.tokenExpiry = time.Now().Add(time.Duration(login.Auth.LeaseDuration)*time.Second - time.Minute)
+	return nil
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+)
+
+// interaction is a single recorded API request and its response. Request headers are deliberately not stored
+// so that tokens never end up in fixture files.
+type interaction struct {
+	Method       string      `json:"method"`
+	URL          string      `json:"url"`
+	RequestBody  string      `json:"requestBody,omitempty"`
+	Status       int         `json:"status"`
+	Header       http.Header `json:"header"`
+	ResponseBody string      `json:"responseBody"`
+	replayed     bool
+}
+
+// cassette is a set of interactions persisted as a JSON fixture file.
+type cassette struct {
+	path         string
+	mu           sync.Mutex
+	interactions []*interaction
+}
+
+func loadCassette(path string) (*cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	c := &cassette{path: path}
+	if err := json.Unmarshal(data, &c.interactions); err != nil {
+		return nil, fmt.Errorf("parsing cassette %s: %w", path, err)
+	}
+	return c, nil
+}
+
+func (c *cassette) save() error {
+	data, err := json.MarshalIndent(c.interactions, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(c.path, data, 0o600)
+}
+
+// recordingTransport forwards requests to base and appends every interaction to a cassette file.
+type recordingTransport struct {
+	base     http.RoundTripper
+	cassette *cassette
+}
+
+func newRecordingTransport(base http.RoundTripper, path string) *recordingTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &recordingTransport{base: base, cassette: &cassette{path: path}}
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := readBody(&resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	t.cassette.mu.Lock()
+	defer t.cassette.mu.Unlock()
+	t.cassette.interactions = append(t.cassette.interactions, &interaction{
+		Method:       req.Method,
+		URL:          req.URL.String(),
+		RequestBody:  reqBody,
+		Status:       resp.StatusCode,
+		Header:       resp.Header,
+		ResponseBody: respBody,
+	})
+	// saved after every interaction, as fatal errors exit without running deferred code
+	if err := t.cassette.save(); err != nil {
+		return nil, fmt.Errorf("saving cassette: %w", err)
+	}
+	return resp, nil
+}
+
+// replayingTransport answers requests from a cassette without touching the network. Interactions are matched
+// by method, URL and request body, in recorded order, so repeated identical requests replay in sequence.
+type replayingTransport struct {
+	cassette *cassette
+}
+
+func newReplayingTransport(path string) (*replayingTransport, error) {
+	c, err := loadCassette(path)
+	if err != nil {
+		return nil, err
+	}
+	return &replayingTransport{cassette: c}, nil
+}
+
+func (t *replayingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	t.cassette.mu.Lock()
+	defer t.cassette.mu.Unlock()
+	for _, i := range t.cassette.interactions {
+		if i.replayed || i.Method != req.Method || i.URL != req.URL.String() || i.RequestBody != reqBody {
+			continue
+		}
+		i.replayed = true
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", i.Status, http.StatusText(i.Status)),
+			StatusCode:    i.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        i.Header.Clone(),
+			Body:          io.NopCloser(bytes.NewBufferString(i.ResponseBody)),
+			ContentLength: int64(len(i.ResponseBody)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("no recorded interaction for %s %s", req.Method, req.URL)
+}
+
+// readBody drains body and replaces it with an equivalent reader so it can still be consumed.
+func readBody(body *io.ReadCloser) (string, error) {
+	if *body == nil || *body == http.NoBody {
+		return "", nil
+	}
+	data, err := io.ReadAll(*body)
+	_ = (*body).Close()
+	if err != nil {
+		return "", err
+	}
+	*body = io.NopCloser(bytes.NewReader(data))
+	return string(data), nil
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+// TestReplayRun replays a run recorded with -record against GitHub, in which one PR is merged and the other has a
+// failing check.
+func TestReplayRun(t *testing.T) {
+	replay, err := newReplayingTransport(filepath.Join("testdata", "github_run.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	usage := newCountingTransport(replay)
+	provider, err := newProvider("github", "", nil, usage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := newTestRunner(t, provider)
+	r.usage = usage
+	r.opts.org, r.opts.user, r.opts.author, r.opts.defaultComment = "acme", "bob", "app/renovate", "LGTM"
+
+	if err := r.run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"https://github.com/acme/svc-a/pull/1": string(decisionMerged),
+		"https://github.com/acme/svc-b/pull/2": string(decisionSkipped) + " checks not succeeded",
+	}
+	if len(r.report.results) != len(want) {
+		t.Fatalf("got %d results, want %d: %+v", len(r.report.results), len(want), r.report.results)
+	}
+	for _, result := range r.report.results {
+		got := string(result.Decision)
+		if result.Reason != "" {
+			got += " " + result.Reason
+		}
+		if got != want[result.PR.URL] {
+			t.Errorf("%s: got %q, want %q", result.PR.URL, got, want[result.PR.URL])
+		}
+	}
+	for _, i := range replay.cassette.interactions {
+		if !i.replayed && i.Method != "GET" {
+			t.Errorf("recorded %s %s was not replayed", i.Method, i.URL)
+		}
+	}
+}
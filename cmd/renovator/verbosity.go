@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// verbosity is the amount of output: -1 for errors only (-q), 0 by default, 1 for -v adding PR details and rate
+// limits and 2 for -vv also logging every API request.
+var verbosity int
+
+// infof prints output shown by default.
+func infof(format string, args ...any) {
+	if verbosity >= 0 {
+		fmt.Printf(format, args...)
+	}
+}
+
+// verbosef prints output shown with -v.
+func verbosef(format string, args ...any) {
+	if verbosity >= 1 {
+		fmt.Printf(format, args...)
+	}
+}
+
+// loggingTransport logs the method, URL, status and duration of every request with -vv.
+type loggingTransport struct {
+	base http.RoundTripper
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	elapsed := time.Since(start).Round(time.Millisecond)
+	if err != nil {
+		log.Printf("%s %s failed after %s: %v", req.Method, req.URL.Redacted(), elapsed, err)
+		return nil, err
+	}
+	log.Printf("%s %s -> %s in %s", req.Method, req.URL.Redacted(), resp.Status, elapsed)
+	return resp, nil
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// verifyInterval is how often the checks of merge commits are polled with -verify-merge.
+const verifyInterval = 30 * time.Second
+
+// mergeChecker is implemented by providers that can evaluate the checks of the commit a PR was merged as.
+type mergeChecker interface {
+	// MergeChecks reports whether the checks of the merge commit of pr have all completed and whether they passed.
+	MergeChecks(ctx context.Context, pr *PullRequest) (completed, passed bool, err error)
+}
+
+// verifyMerges waits up to -verify-merge for the checks of the commits the PRs merged during the run became,
+// reporting the merges whose checks fail as broken so that they can be alerted on.
+func (r *runner) verifyMerges(ctx context.Context) {
+	pending := r.toVerify
+	r.toVerify = nil
+	if r.opts.verifyMerge <= 0 || len(pending) == 0 {
+		return
+	}
+	checker, ok := r.provider.(mergeChecker)
+	if !ok {
+		log.Printf("Verifying merges is not supported by the provider of %s", r.target)
+		return
+	}
+
+	infof("\n"+msg("Waiting up to %s for the checks of %d merged PRs")+"\n", r.opts.verifyMerge, len(pending))
+	deadline := time.Now().Add(r.opts.verifyMerge)
+	for {
+		var running []*PullRequest
+		for _, pr := range pending {
+			completed, passed, err := checker.MergeChecks(ctx, pr)
+			switch {
+			case err != nil:
+				log.Printf(paint(decisionFailed, "Error fetching the checks of the merge of %s: %v"), pr.URL, err)
+			case !completed:
+				running = append(running, pr)
+			case passed:
+				r.printf(paint(decisionMerged, msg("Checks passed after merging %s"))+"\n", pr.URL)
+			default:
+				log.Printf(paint(decisionFailed, "Checks failed after merging %s"), pr.URL)
+				reason := "checks failed after merging"
+				if revertURL := r.revertBroken(ctx, pr); revertURL != "" {
+					reason += ", reverted in " + revertURL
+				}
+				r.broken(prResult{Target: r.target, PR: pr, Decision: decisionMerged, Reason: reason})
+			}
+		}
+		pending = running
+		if len(pending) == 0 || time.Now().Add(verifyInterval).After(deadline) {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(verifyInterval):
+		}
+	}
+	for _, pr := range pending {
+		infof(msg("Checks of the merge of %s are still running after %s")+"\n", pr.URL, r.opts.verifyMerge)
+	}
+}
+
+// broken reports a merge whose checks failed.
+func (r *runner) broken(result prResult) {
+	r.report.addBroken(result)
+	event := prEvent(result)
+	event.Type = "pr.broken"
+	r.events.emit(event)
+}
+
+// brokenSummary is the alert summary of a merge whose checks failed.
+func brokenSummary(result prResult) string {
+	return fmt.Sprintf("Renovate PR %s/%s#%d %s: %s",
+		result.PR.Org, result.PR.Repo, result.PR.Number, result.Reason, result.PR.URL)
+}
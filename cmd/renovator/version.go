@@ -0,0 +1,217 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v50/github"
+	"golang.org/x/oauth2"
+)
+
+// version is the released version, set at build time with -ldflags "-X main.version=v1.2.3".
+var version = "dev"
+
+// releasePublicKey is the base64 ed25519 key release checksums are signed with, set at build time with
+// -ldflags "-X main.releasePublicKey=...". Without it self-update only verifies checksums.
+var releasePublicKey = ""
+
+const (
+	releaseOwner = "tonisojandu"
+	releaseRepo  = "renovator-go"
+)
+
+// versionInfo describes the build for "renovator version".
+func versionInfo() string {
+	info := fmt.Sprintf("renovator %s", version)
+	var details []string
+	if build, ok := debug.ReadBuildInfo(); ok {
+		settings := map[string]string{}
+		for _, s := range build.Settings {
+			settings[s.Key] = s.Value
+		}
+		if commit := settings["vcs.revision"]; commit != "" {
+			if settings["vcs.modified"] == "true" {
+				commit += "-dirty"
+			}
+			details = append(details, "commit "+commit)
+		}
+		if built := settings["vcs.time"]; built != "" {
+			details = append(details, "committed "+built)
+		}
+	}
+	details = append(details, runtime.Version(), runtime.GOOS+"/"+runtime.GOARCH)
+	return info + " (" + strings.Join(details, ", ") + ")"
+}
+
+// releaseAssetName is the name of the release asset holding the binary for this platform.
+func releaseAssetName() string {
+	name := fmt.Sprintf("renovator_%s_%s", runtime.GOOS, runtime.GOARCH)
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+// runSelfUpdate implements "renovator self-update", replacing the running binary with the one of the latest
+// GitHub release after verifying its checksum, and the signature of the checksums when a release key is built in.
+func runSelfUpdate(args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("usage: renovator self-update")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	httpClient := http.DefaultClient
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+		httpClient = bearerClient(oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token}), http.DefaultTransport)
+	}
+	release, _, err := github.NewClient(httpClient).Repositories.GetLatestRelease(ctx, releaseOwner, releaseRepo)
+	if err != nil {
+		return fmt.Errorf("finding the latest release: %w", err)
+	}
+	latest := release.GetTagName()
+	if latest == version {
+		fmt.Printf(msg("renovator %s is the latest version")+"\n", version)
+		return nil
+	}
+
+	assets := map[string]string{}
+	for _, asset := range release.Assets {
+		assets[asset.GetName()] = asset.GetBrowserDownloadURL()
+	}
+	name := releaseAssetName()
+	if assets[name] == "" || assets["checksums.txt"] == "" {
+		return fmt.Errorf("release %s has no %s or checksums.txt", latest, name)
+	}
+
+	checksums, err := download(ctx, assets["checksums.txt"])
+	if err != nil {
+		return err
+	}
+	if releasePublicKey != "" {
+		if err := verifyChecksumsSignature(ctx, checksums, assets["checksums.txt.sig"]); err != nil {
+			return err
+		}
+	} else {
+		fmt.Println(msg("Warning: this build has no release key, only verifying the checksum"))
+	}
+	expected, err := checksumOf(checksums, name)
+	if err != nil {
+		return err
+	}
+	binary, err := download(ctx, assets[name])
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(binary)
+	if hex.EncodeToString(sum[:]) != expected {
+		return fmt.Errorf("checksum of %s does not match checksums.txt", name)
+	}
+
+	if err := replaceExecutable(binary); err != nil {
+		return err
+	}
+	fmt.Printf(msg("Updated renovator from %s to %s")+"\n", version, latest)
+	return nil
+}
+
+// checksumOf finds the SHA-256 checksum of name in sha256sum formatted checksums.
+func checksumOf(checksums []byte, name string) (string, error) {
+	for _, line := range strings.Split(string(checksums), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	return "", fmt.Errorf("checksums.txt has no checksum for %s", name)
+}
+
+// verifyChecksumsSignature verifies the base64 ed25519 signature of the checksums downloaded from signatureURL.
+func verifyChecksumsSignature(ctx context.Context, checksums []byte, signatureURL string) error {
+	if signatureURL == "" {
+		return fmt.Errorf("release has no checksums.txt.sig")
+	}
+	key, err := base64.StdEncoding.DecodeString(releasePublicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return fmt.Errorf("invalid release key")
+	}
+	encoded, err := download(ctx, signatureURL)
+	if err != nil {
+		return err
+	}
+	signature, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(encoded)))
+	if err != nil {
+		return fmt.Errorf("decoding checksums.txt.sig: %w", err)
+	}
+	if !ed25519.Verify(key, checksums, signature) {
+		return fmt.Errorf("signature of checksums.txt does not match the release key")
+	}
+	return nil
+}
+
+func download(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("downloading %s: %s", url, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// replaceExecutable replaces the running binary with binary. The new binary is written next to it and renamed
+// over it, moving the old one aside first as Windows cannot overwrite a running executable.
+func replaceExecutable(binary []byte) error {
+	path, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	if path, err = filepath.EvalSymlinks(path); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".renovator-update-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(binary); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o755); err != nil {
+		return err
+	}
+	old := path + ".old"
+	_ = os.Remove(old)
+	if err := os.Rename(path, old); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		_ = os.Rename(old, path)
+		return err
+	}
+	_ = os.Remove(old)
+	return nil
+}
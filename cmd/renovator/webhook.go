@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"net/http"
+	"os"
+	"time"
+)
+
+// webhookTimeout bounds how long delivering a single event may take.
+const webhookTimeout = 10 * time.Second
+
+// webhookConfig configures the webhook events are posted to.
+type webhookConfig struct {
+	URL string `yaml:"url"`
+	// SecretVariable is the environment variable holding the secret events are signed with.
+	SecretVariable string `yaml:"secretVariable"`
+}
+
+// webhookEvent is the JSON body of an event. Type is one of run.started, pr.merged, pr.skipped, pr.failed,
+// pr.broken and run.finished.
+type webhookEvent struct {
+	Type   string         `json:"type"`
+	Time   time.Time      `json:"time"`
+	Target string         `json:"target,omitempty"`
+	PR     *webhookPR     `json:"pr,omitempty"`
+	Reason string         `json:"reason,omitempty"`
+	Totals map[string]int `json:"totals,omitempty"`
+	// ReasonCode classifies Reason, see reasonCode.
+	ReasonCode reasonCode `json:"reasonCode,omitempty"`
+}
+
+type webhookPR struct {
+	Org    string `json:"org"`
+	Repo   string `json:"repo"`
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	URL    string `json:"url"`
+	// RunURL is the workflow run triggered after merging the PR.
+	RunURL string `json:"runUrl,omitempty"`
+}
+
+// webhookEmitter posts events to a webhook. When a secret is set, the body is signed with HMAC-SHA256 in the
+// X-Renovator-Signature-256 header as sha256=<hex>, like GitHub webhooks.
+type webhookEmitter struct {
+	url    string
+	secret string
+}
+
+func newWebhookEmitter(c *webhookConfig) *webhookEmitter {
+	if c == nil || c.URL == "" {
+		return nil
+	}
+	return &webhookEmitter{url: c.URL, secret: os.Getenv(c.SecretVariable)}
+}
+
+// emit delivers event, logging failures. A nil emitter does nothing.
+func (w *webhookEmitter) emit(event webhookEvent) {
+	if w == nil {
+		return
+	}
+	event.Time = time.Now().UTC()
+	body, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Error encoding %s event: %v", event.Type, err)
+		return
+	}
+	header := http.Header{}
+	header.Set("X-Renovator-Event", event.Type)
+	if w.secret != "" {
+		mac := hmac.New(sha256.New, []byte(w.secret))
+		mac.Write(body)
+		header.Set("X-Renovator-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), webhookTimeout)
+	defer cancel()
+	if err := postBody(ctx, w.url, body, header); err != nil {
+		log.Printf("Error posting %s event to webhook: %v", event.Type, err)
+	}
+}
+
+// prEvent is the event for the result of a PR.
+func prEvent(result prResult) webhookEvent {
+	return webhookEvent{
+		Type:   "pr." + string(result.Decision),
+		Target: result.Target,
+		PR: &webhookPR{
+			Org:    result.PR.Org,
+			Repo:   result.PR.Repo,
+			Number: result.PR.Number,
+			Title:  result.PR.Title,
+			URL:    result.PR.URL,
+			RunURL: result.RunURL,
+		},
+		Reason:     result.Reason,
+		ReasonCode: result.Code,
+	}
+}